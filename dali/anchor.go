@@ -0,0 +1,151 @@
+package dali
+
+import (
+	"fmt"
+	"net/url"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Anchor is a link.  It either calls a Go handler, goes to an internal route
+// of the application, or opens an external URL in the system browser - the
+// Window itself never navigates away from the application page.
+type Anchor struct {
+	ID        string
+	Text      string
+	Href      string
+	StyleName string
+	handler   func()
+	click     string
+	BaseElement
+}
+
+// NewLink creates an Anchor calling handler when it is clicked
+func NewLink(name, text string, handler func()) *Anchor {
+	return &Anchor{ID: name, Text: text, Href: "#", handler: handler, click: functionName("link")}
+}
+
+// NewRouteLink creates an Anchor going to route, such as /users/42, within the application
+func NewRouteLink(name, text, route string) *Anchor {
+	return &Anchor{ID: name, Text: text, Href: "#" + route}
+}
+
+// NewExternalLink creates an Anchor opening an http, https or mailto URL in the
+// system browser.  Any other URL is an error.
+func NewExternalLink(name, text, link string) (*Anchor, error) {
+	if _, err := externalURL(link); err != nil {
+		return nil, err
+	}
+	return &Anchor{ID: name, Text: text, Href: link}, nil
+}
+
+// String for Anchor
+func (a *Anchor) String() string {
+	attrs := attribute("id", a.ID) + attribute("href", a.Href)
+	if a.StyleName != "" {
+		attrs += attribute("style", a.StyleName)
+	}
+	onclick := a.Events.script("click")
+	if a.handler != nil {
+		onclick = strings.TrimSuffix(fmt.Sprintf("event.preventDefault();%s();%s", a.function(), onclick), ";")
+	}
+	if onclick != "" {
+		attrs += attribute("onclick", onclick)
+	}
+	return fmt.Sprintf(`<a%s%s%s>%s</a>`, attrs, a.attributes(), a.Events.attributes("click"), escape(a.Text))
+}
+
+// function is the name of the JavaScript function bound to the handler
+func (a *Anchor) function() string {
+	if a.click == "" {
+		a.click = functionName("link")
+	}
+	return a.click
+}
+
+// Name of the Anchor
+func (a *Anchor) Name() string { return a.ID }
+
+// Style of the Anchor
+func (a *Anchor) Style() string { return a.StyleName }
+
+// Styles of the Anchor
+func (a *Anchor) Styles() Styles { return parseStyles(a.StyleName) }
+
+// Clickable is true for an Anchor
+func (a *Anchor) Clickable() bool { return true }
+
+// SetText changes the text of the Anchor
+func (a *Anchor) SetText(text string) error {
+	a.Text = text
+	return a.live.setText(text)
+}
+
+// SetHref changes where the Anchor goes: an internal route starting with #, or
+// an http, https or mailto URL.  Any other href is an error.
+func (a *Anchor) SetHref(href string) error {
+	if !strings.HasPrefix(href, "#") {
+		if _, err := externalURL(href); err != nil {
+			return err
+		}
+	}
+	a.Href = href
+	return a.live.change(mirrorAttr("href", href), `el.setAttribute("href",%s);`, href)
+}
+
+// SetStyle sets a CSS property of the Anchor, or removes it if value is empty
+func (a *Anchor) SetStyle(property, value string) error {
+	return a.live.setStyle(&a.StyleName, property, value)
+}
+
+// Show displays the Anchor
+func (a *Anchor) Show() error { return a.live.show(&a.StyleName) }
+
+// Hide hides the Anchor
+func (a *Anchor) Hide() error { return a.live.hide(&a.StyleName) }
+
+// boundHandlers binds the handler of the Anchor, along with those registered with On
+func (a *Anchor) boundHandlers() map[string]interface{} {
+	handlers := a.Events.boundHandlers()
+	if a.handler != nil {
+		handlers[a.function()] = a.handler
+	}
+	return handlers
+}
+
+// OpenURL opens an http, https or mailto URL in the system browser or mail client
+func OpenURL(link string) error {
+	u, err := externalURL(link)
+	if err != nil {
+		return err
+	}
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", u.String())
+	case "darwin":
+		cmd = exec.Command("open", u.String())
+	default:
+		cmd = exec.Command("xdg-open", u.String())
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	// reap the launcher so it does not linger as a zombie
+	go cmd.Wait()
+	return nil
+}
+
+// externalURL parses link, which must be an http, https or mailto URL
+func externalURL(link string) (*url.URL, error) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return nil, err
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https", "mailto":
+		return u, nil
+	}
+	return nil, fmt.Errorf("%q is not an http, https or mailto URL", link)
+}
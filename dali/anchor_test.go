@@ -0,0 +1,68 @@
+package dali
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAnchorKinds(t *testing.T) {
+	clicked := 0
+	link := NewLink("save", "Save", func() { clicked++ })
+	route := NewRouteLink("user", "User", "/users/42")
+	external, err := NewExternalLink("docs", "Docs", "https://example.com/docs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewExternalLink("bad", "Bad", "javascript:alert(1)"); err == nil {
+		t.Errorf("expected a javascript: link to be refused")
+	}
+
+	expected := `<a id="save" href="#" onclick="event.preventDefault();` + link.function() + `()">Save</a>`
+	if got := link.String(); got != expected {
+		t.Errorf(`expected "%s" but got "%s"`, expected, got)
+	}
+	expected = `<a id="user" href="#/users/42">User</a>`
+	if got := route.String(); got != expected {
+		t.Errorf(`expected "%s" but got "%s"`, expected, got)
+	}
+	expected = `<a id="docs" href="https://example.com/docs">Docs</a>`
+	if got := external.String(); got != expected {
+		t.Errorf(`expected "%s" but got "%s"`, expected, got)
+	}
+
+	w := startedWindow(t, &fakeUI{}, link, route, external)
+	handler, ok := w.handlers[link.function()].(func())
+	if !ok {
+		t.Fatalf("expected the link handler to be bound as %s", link.function())
+	}
+	handler()
+	if clicked != 1 {
+		t.Errorf("expected the handler to be called once but it was called %d times", clicked)
+	}
+	if _, ok := w.handlers["dali_open"].(func(string) error); !ok {
+		t.Errorf("expected external links to be opened through dali_open")
+	}
+	if _, ok := w.handlers[route.function()]; ok || route.handler != nil {
+		t.Errorf("expected a route link to have no Go handler")
+	}
+}
+
+func TestAnchorSetHref(t *testing.T) {
+	ui := &fakeUI{}
+	route := NewRouteLink("user", "User", "/users/42")
+	w := startedWindow(t, ui, route)
+	if err := route.SetHref("mailto:ann@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if last := ui.evals[len(ui.evals)-1]; !strings.Contains(last, `el.setAttribute("href","mailto:ann@example.com");`) {
+		t.Errorf(`expected the href to change in the page but got "%s"`, last)
+	}
+	inStep(t, w, "changing the href")
+	if err := route.SetHref("file:///etc/passwd"); err == nil || route.Href != "mailto:ann@example.com" {
+		t.Errorf(`expected a file: href to be refused but got "%s"`, route.Href)
+	}
+	if err := route.SetHref("#/home"); err != nil || route.Href != "#/home" {
+		t.Errorf(`expected "%s" but got "%s" (%v)`, "#/home", route.Href, err)
+	}
+	inStep(t, w, "going to an internal route")
+}
@@ -0,0 +1,133 @@
+package dali
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Attributes maps attribute names to their values.  They are rendered sorted
+// by name with escaped values; id, style and class are left to the element.
+type Attributes map[string]string
+
+// reservedAttributes are rendered by the element itself from its own fields
+var reservedAttributes = map[string]bool{"id": true, "style": true, "class": true}
+
+//String for Attributes
+func (a Attributes) String() string {
+	names := []string{}
+	for name := range a {
+		if !reservedAttributes[strings.ToLower(name)] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	attrs := ""
+	for _, name := range names {
+		attrs += attribute(name, a[name])
+	}
+	return attrs
+}
+
+// attributes renders the class list and Attributes of the element
+func (b *BaseElement) attributes() string {
+	attrs := ""
+	if len(b.classes) > 0 {
+		attrs = attribute("class", strings.Join(b.classes, " "))
+	}
+	return attrs + b.Attributes.String()
+}
+
+//Class is the CSS class list of the element, separated by spaces
+func (b *BaseElement) Class() string { return strings.Join(b.classes, " ") }
+
+// Attr returns the value of an attribute
+func (b *BaseElement) Attr(name string) string { return b.Attributes[name] }
+
+// SetAttr sets an attribute of the element
+func (b *BaseElement) SetAttr(name, value string) error {
+	if !attributeName.MatchString(name) || reservedAttributes[strings.ToLower(name)] {
+		return fmt.Errorf("%q is not an attribute that can be set", name)
+	}
+	if urlAttributes[strings.ToLower(name)] {
+		if err := checkURL(value); err != nil {
+			return err
+		}
+	}
+	if b.Attributes == nil {
+		b.Attributes = Attributes{}
+	}
+	b.Attributes[name] = value
+	return b.live.change(func(t *tree, n *vnode) { n.setAttr(strings.ToLower(name), value) }, `el.setAttribute(%s,%s);`, name, value)
+}
+
+// RemoveAttr removes an attribute from the element
+func (b *BaseElement) RemoveAttr(name string) error {
+	delete(b.Attributes, name)
+	return b.live.change(func(t *tree, n *vnode) { n.removeAttr(strings.ToLower(name)) }, `el.removeAttribute(%s);`, name)
+}
+
+// SetData sets a data-* attribute: SetData("row", "3") sets data-row="3"
+func (b *BaseElement) SetData(key, value string) error {
+	return b.SetAttr("data-"+key, value)
+}
+
+// SetAria sets an aria-* attribute: SetAria("label", "Close") sets aria-label="Close"
+func (b *BaseElement) SetAria(name, value string) error {
+	return b.SetAttr("aria-"+name, value)
+}
+
+// SetTitle sets the tooltip of the element
+func (b *BaseElement) SetTitle(title string) error {
+	return b.SetAttr("title", title)
+}
+
+// SetTabIndex sets the position of the element in the keyboard focus order
+func (b *BaseElement) SetTabIndex(index int) error {
+	return b.SetAttr("tabindex", strconv.Itoa(index))
+}
+
+// SetHidden sets or removes the hidden attribute
+func (b *BaseElement) SetHidden(hidden bool) error {
+	if hidden {
+		return b.SetAttr("hidden", "")
+	}
+	return b.RemoveAttr("hidden")
+}
+
+// AddClass adds a CSS class to the element
+func (b *BaseElement) AddClass(class string) error {
+	if b.HasClass(class) {
+		return nil
+	}
+	b.classes = append(b.classes, class)
+	return b.live.change(mirrorAttr("class", b.Class()), `el.classList.add(%s);`, class)
+}
+
+// RemoveClass removes a CSS class from the element
+func (b *BaseElement) RemoveClass(class string) error {
+	for i, c := range b.classes {
+		if c == class {
+			b.classes = append(b.classes[:i], b.classes[i+1:]...)
+			break
+		}
+	}
+	return b.live.change(mirrorAttr("class", b.Class()), `el.classList.remove(%s);`, class)
+}
+
+// HasClass is true when the element has the CSS class
+func (b *BaseElement) HasClass(class string) bool {
+	for _, c := range b.classes {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+// Focus moves the keyboard focus to the element
+func (b *BaseElement) Focus() error {
+	return b.live.eval(`el.focus();`)
+}
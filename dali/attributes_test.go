@@ -0,0 +1,61 @@
+package dali
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAttributesRenderSortedAndEscaped(t *testing.T) {
+	d := NewDiv("panel")
+	d.Attributes = Attributes{"ID": "other", "style": "color:red", "role": "region"}
+	for _, set := range []func() error{
+		func() error { return d.SetData("row", `3"`) },
+		func() error { return d.SetAria("label", "Panel") },
+		func() error { return d.SetTitle("<tip>") },
+		func() error { return d.SetTabIndex(-1) },
+		func() error { return d.SetHidden(true) },
+		func() error { return d.AddClass("card") },
+		func() error { return d.AddClass("wide") },
+		func() error { return d.AddClass("card") },
+	} {
+		if err := set(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected := `<div id="panel" class="card wide" aria-label="Panel" data-row="3&#34;" hidden="" role="region" tabindex="-1" title="&lt;tip&gt;">`
+	if html := d.String(); !strings.HasPrefix(html, expected) {
+		t.Errorf(`expected "%s" but got "%s"`, expected, html)
+	}
+	if d.Class() != "card wide" || !d.HasClass("wide") {
+		t.Errorf(`expected "%s" but got "%s"`, "card wide", d.Class())
+	}
+
+	d.RemoveClass("card")
+	d.SetHidden(false)
+	d.RemoveAttr("role")
+	expected = `<div id="panel" class="wide" aria-label="Panel" data-row="3&#34;" tabindex="-1" title="&lt;tip&gt;">`
+	if html := d.String(); !strings.HasPrefix(html, expected) {
+		t.Errorf(`expected "%s" but got "%s"`, expected, html)
+	}
+}
+
+func TestSetAttrRefusesUnsafeAttributes(t *testing.T) {
+	d := NewDiv("panel")
+	for name, value := range map[string]string{
+		"id":            "other",
+		"Style":         "color:red",
+		`x" onload="a`:  "1",
+		"href":          "javascript:alert(1)",
+		"formaction":    "javascript:alert(1)",
+		"data-a b":      "1",
+		"":              "1",
+		"onclick=alert": "1",
+	} {
+		if err := d.SetAttr(name, value); err == nil {
+			t.Errorf(`expected %s="%s" to be refused`, name, value)
+		}
+	}
+	if len(d.Attributes) != 0 {
+		t.Errorf("expected no attributes but got %v", d.Attributes)
+	}
+}
@@ -0,0 +1,33 @@
+package dali
+
+// BaseElement supplies safe defaults for the whole Element interface, along
+// with event binding and live changes to the page.  Embed it in a custom
+// element and implement String to render it, overriding any other method
+// the element needs; Name must return the rendered id for live changes.
+type BaseElement struct {
+	Attributes Attributes
+	classes    []string
+	Events
+	live
+}
+
+//String renders nothing
+func (b *BaseElement) String() string { return "" }
+
+//Style is empty
+func (b *BaseElement) Style() string { return "" }
+
+//Name is empty
+func (b *BaseElement) Name() string { return "" }
+
+//Clickable is false
+func (b *BaseElement) Clickable() bool { return false }
+
+//Styles returns an empty Styles
+func (b *BaseElement) Styles() Styles { return Styles{} }
+
+//Children returns an empty Elements
+func (b *BaseElement) Children() *Elements { return &Elements{slice: []*Element{}} }
+
+//Bindings returns nil
+func (b *BaseElement) Bindings() *Binding { return nil }
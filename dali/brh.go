@@ -0,0 +1,111 @@
+package dali
+
+import "fmt"
+
+// HeaderLevel enums header sizes
+type HeaderLevel int
+
+const (
+	//H1 header
+	H1 = HeaderLevel(1)
+	//H2 header
+	H2 = HeaderLevel(2)
+	//H3 header
+	H3 = HeaderLevel(3)
+	//H4 header
+	H4 = HeaderLevel(4)
+)
+
+// BR a break tag
+type BR struct {
+	StyleName string
+	BaseElement
+}
+
+//LineBreak generates a BR tag
+func LineBreak() *BR {
+	return &BR{}
+}
+
+func (br *BR) String() string {
+	style := ""
+	if br.StyleName != "" {
+		style = attribute("style", br.StyleName)
+	}
+	return fmt.Sprintf("<br%s%s/>", style, br.attributes())
+}
+
+//Style of the BR
+func (br *BR) Style() string { return br.StyleName }
+
+//Styles of the BR
+func (br *BR) Styles() Styles { return parseStyles(br.StyleName) }
+
+//Header is a header
+type Header struct {
+	StyleName string
+	ID        string
+	Level     HeaderLevel
+	Text      string
+	markup    bool
+	BaseElement
+}
+
+func (h *Header) String() string {
+	style := ""
+	name := ""
+	if h.StyleName != "" {
+		style = attribute("style", h.StyleName)
+	}
+	if h.ID != "" {
+		name = attribute("id", h.Name())
+	}
+	text := escape(h.Text)
+	if h.markup {
+		text = h.Text
+	}
+	return fmt.Sprintf(`<H%d%s%s%s%s>%s</H%d>`, h.Level, name, style, h.attributes(), h.Events.attributes(), text, h.Level)
+}
+
+//NewHeader produces a new header element
+func NewHeader(level HeaderLevel, name, text string) *Header {
+	return &Header{
+		Text:  text,
+		ID:    name,
+		Level: level,
+	}
+}
+
+//Name of header
+func (h *Header) Name() string { return h.ID }
+
+//Style returns the style of the Header
+func (h *Header) Style() string { return h.StyleName }
+
+//Styles returns the style of the Header as Styles
+func (h *Header) Styles() Styles { return parseStyles(h.StyleName) }
+
+// SetText replaces the text of the Header
+func (h *Header) SetText(text string) error {
+	h.Text = text
+	h.markup = false
+	return h.live.setText(text)
+}
+
+// SetHTML replaces the contents of the Header with markup
+func (h *Header) SetHTML(html string) error {
+	h.Text = html
+	h.markup = true
+	return h.live.setHTML(html)
+}
+
+// SetStyle sets a CSS property of the Header, or removes it if value is empty
+func (h *Header) SetStyle(property, value string) error {
+	return h.live.setStyle(&h.StyleName, property, value)
+}
+
+// Show displays the Header
+func (h *Header) Show() error { return h.live.show(&h.StyleName) }
+
+// Hide hides the Header
+func (h *Header) Hide() error { return h.live.hide(&h.StyleName) }
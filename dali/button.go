@@ -0,0 +1,101 @@
+package dali
+
+import (
+	"fmt"
+	"strings"
+)
+
+//Button type
+type Button struct {
+	ID              string
+	ButtonText      string
+	StyleExpression string
+	markup          bool
+	BaseElement
+	Binding
+}
+
+func (b *Button) String() string {
+	style := ""
+	if b.StyleExpression != "" {
+		style = attribute("style", b.StyleExpression)
+	}
+	// a click handler registered with On runs after the bound function
+	onclick := b.Events.script("click")
+	if bound := jsCall(b.Binding.FunctionName); bound != "" {
+		onclick = strings.TrimSuffix(fmt.Sprintf("%s;%s", bound, onclick), ";")
+	}
+	if onclick != "" {
+		onclick = attribute("onclick", onclick)
+	}
+	label := escape(b.ButtonText)
+	if b.markup {
+		label = b.ButtonText
+	}
+	return fmt.Sprintf(`<button id="%s"%s%s%s%s>%s</button>`, escape(b.Name()), onclick, style, b.attributes(), b.Events.attributes("click"), label)
+}
+
+//Name returns the ID of the button
+func (b *Button) Name() string {
+	return b.ID
+}
+
+//Style set the style of the button
+func (b *Button) Style() string {
+	return b.StyleExpression
+}
+
+//Styles of the button
+func (b *Button) Styles() Styles { return parseStyles(b.StyleExpression) }
+
+// Clickable returns true for buttons
+func (b *Button) Clickable() bool {
+	return true
+}
+
+//OnClick returns the name of the onclick function
+func (b *Button) OnClick() string {
+	return fmt.Sprintf("on_%s()", b.Name())
+}
+
+// BindFunction will bind the button to this go function
+func (b *Button) BindFunction(f func()) {
+	b.Binding.BoundFunction = f
+}
+
+//Bindings returns the bindings for the button
+func (b *Button) Bindings() *Binding { return &b.Binding }
+
+//NewButton creates a new button
+func NewButton(label, name, funcName string) *Button {
+	return &Button{
+		ID:         name,
+		ButtonText: label,
+		Binding:    Binding{FunctionName: funcName},
+	}
+}
+
+// SetText replaces the label of the Button
+func (b *Button) SetText(text string) error {
+	b.ButtonText = text
+	b.markup = false
+	return b.live.setText(text)
+}
+
+// SetHTML replaces the label of the Button with markup, such as an icon and text
+func (b *Button) SetHTML(html string) error {
+	b.ButtonText = html
+	b.markup = true
+	return b.live.setHTML(html)
+}
+
+// SetStyle sets a CSS property of the Button, or removes it if value is empty
+func (b *Button) SetStyle(property, value string) error {
+	return b.live.setStyle(&b.StyleExpression, property, value)
+}
+
+// Show displays the Button
+func (b *Button) Show() error { return b.live.show(&b.StyleExpression) }
+
+// Hide hides the Button
+func (b *Button) Hide() error { return b.live.hide(&b.StyleExpression) }
@@ -0,0 +1,124 @@
+package dali
+
+import "fmt"
+
+// Canvas element
+type Canvas struct {
+	Width, Height int
+	ID            string
+	StyleName     string
+	pointer       string
+	onPointer     []func(PointerEvent)
+	order         sequencer
+	BaseElement
+}
+
+// PointerEvent is a pointer or wheel event on a Canvas.  X and Y are measured
+// in canvas pixels from the top left corner of the drawing surface.
+type PointerEvent struct {
+	Type        string  `json:"type"` // pointerdown, pointermove, pointerup or wheel
+	X           float64 `json:"x"`
+	Y           float64 `json:"y"`
+	PointerID   int     `json:"pointerId"`
+	PointerType string  `json:"pointerType"` // mouse, pen or touch
+	Button      int     `json:"button"`
+	Buttons     int     `json:"buttons"`
+	DeltaX      float64 `json:"deltaX"`
+	DeltaY      float64 `json:"deltaY"`
+	Alt         bool    `json:"alt"`
+	Ctrl        bool    `json:"ctrl"`
+	Shift       bool    `json:"shift"`
+	Meta        bool    `json:"meta"`
+}
+
+// pointerMessage is a PointerEvent as the page sends it, numbered in the order the events happened
+type pointerMessage struct {
+	PointerEvent
+	sequenced
+}
+
+//NewCanvas creates a new Canvas
+func NewCanvas(width, height int, name string) *Canvas {
+	return &Canvas{
+		ID:     name,
+		Width:  width,
+		Height: height,
+	}
+}
+
+func (c *Canvas) String() string {
+	style := ""
+	if c.StyleName != "" {
+		style = attribute("style", c.Style())
+	}
+	events := ""
+	if c.pointer != "" {
+		for _, e := range []string{"pointerdown", "pointermove", "pointerup", "wheel"} {
+			events += attribute("on"+e, fmt.Sprintf("dali.send(%s,dali.pointer(event,this))", jsString(c.pointer)))
+		}
+	}
+	return fmt.Sprintf(`<canvas id="%s" width="%dpx" height="%dpx"%s%s%s%s></canvas>`, escape(c.ID), c.Width, c.Height, style, c.attributes(), events, c.Events.attributes())
+}
+
+// OnPointer registers a Go function to receive the pointer and wheel events of the
+// Canvas.  Handlers are called one event at a time, in the order the events
+// happened: an event that reaches Go ahead of an earlier one waits for it.
+// Handlers must be registered before the Window is started.
+func (c *Canvas) OnPointer(handler func(PointerEvent)) {
+	if c.pointer == "" {
+		c.pointer = functionName("pointer")
+	}
+	c.onPointer = append(c.onPointer, handler)
+}
+
+// PointerEvents returns a channel receiving the pointer and wheel events of the
+// Canvas.  Events that arrive while the channel is full are dropped rather than
+// holding up the page.
+func (c *Canvas) PointerEvents() <-chan PointerEvent {
+	events := make(chan PointerEvent, 64)
+	c.OnPointer(func(e PointerEvent) {
+		select {
+		case events <- e:
+		default:
+		}
+	})
+	return events
+}
+
+// boundHandlers binds the pointer events of the Canvas along with those registered with On
+func (c *Canvas) boundHandlers() map[string]interface{} {
+	handlers := c.Events.boundHandlers()
+	if c.pointer != "" {
+		handlers[c.pointer] = func(e pointerMessage) {
+			c.order.apply(e.sequenced, func() {
+				for _, handler := range c.onPointer {
+					handler(e.PointerEvent)
+				}
+			})
+		}
+	}
+	return handlers
+}
+
+// Name of Canvas
+func (c *Canvas) Name() string { return c.ID }
+
+// Clickable is false on Canvas
+func (c *Canvas) Clickable() bool { return false }
+
+//Style of the Canvas
+func (c *Canvas) Style() string { return c.StyleName }
+
+//Styles of the Canvas
+func (c *Canvas) Styles() Styles { return parseStyles(c.StyleName) }
+
+// SetStyle sets a CSS property of the Canvas, or removes it if value is empty
+func (c *Canvas) SetStyle(property, value string) error {
+	return c.live.setStyle(&c.StyleName, property, value)
+}
+
+// Show displays the Canvas
+func (c *Canvas) Show() error { return c.live.show(&c.StyleName) }
+
+// Hide hides the Canvas
+func (c *Canvas) Hide() error { return c.live.hide(&c.StyleName) }
@@ -0,0 +1,272 @@
+package dali
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Context2D is the Go side of a canvas' 2D rendering context.  Calls are
+// evaluated in the page, so the Canvas must belong to a started Window.
+type Context2D struct {
+	canvas    *Canvas
+	recording bool
+	commands  []string
+	images    []string // the urls of the images the commands draw
+	lock      sync.Mutex
+}
+
+// Context returns the 2D drawing context of the Canvas
+func (c *Canvas) Context() *Context2D {
+	return &Context2D{canvas: c}
+}
+
+// Record returns a drawing context that keeps a display list in Go instead of
+// drawing immediately.  Every Eval is a round trip to Chrome, so drawing
+// thousands of primitives is far faster when they are sent with one Flush.
+func (c *Canvas) Record() *Context2D {
+	return &Context2D{canvas: c, recording: true, commands: []string{}, images: []string{}}
+}
+
+// Len is the number of commands waiting to be flushed
+func (ctx *Context2D) Len() int {
+	ctx.lock.Lock()
+	defer ctx.lock.Unlock()
+	return len(ctx.commands)
+}
+
+// Reset discards the recorded commands without drawing them
+func (ctx *Context2D) Reset() {
+	ctx.lock.Lock()
+	defer ctx.lock.Unlock()
+	ctx.commands = []string{}
+	ctx.images = []string{}
+}
+
+// Flush draws every recorded command in a single Eval and empties the display
+// list.  If the Eval fails the commands are kept.
+func (ctx *Context2D) Flush() error {
+	return ctx.flush(`%s`)
+}
+
+// FlushOnAnimationFrame flushes the display list inside a requestAnimationFrame
+// callback, so the commands are drawn together in the browser's next frame
+func (ctx *Context2D) FlushOnAnimationFrame() error {
+	return ctx.flush(`requestAnimationFrame(function(){%s});`)
+}
+
+// flush evaluates the display list wrapped in the wrapper format.  The list is
+// emptied only once the page has taken it, so a failed flush can be retried.
+func (ctx *Context2D) flush(wrapper string) error {
+	ctx.lock.Lock()
+	defer ctx.lock.Unlock()
+	if len(ctx.commands) == 0 {
+		return nil
+	}
+	if err := ctx.eval(fmt.Sprintf(wrapper, strings.Join(ctx.commands, "")), ctx.images); err != nil {
+		return err
+	}
+	ctx.commands = []string{}
+	ctx.images = []string{}
+	return nil
+}
+
+// run draws the script now, or appends it to the display list when recording,
+// along with the urls of the images it draws
+func (ctx *Context2D) run(script string, images ...string) error {
+	if !ctx.recording {
+		return ctx.eval(script, images)
+	}
+	ctx.lock.Lock()
+	defer ctx.lock.Unlock()
+	ctx.commands = append(ctx.commands, script)
+	ctx.images = append(ctx.images, images...)
+	return nil
+}
+
+// eval runs a script in the page with ctx bound to the canvas' 2D context.
+// dali.draw runs it once the images have loaded and what was drawn before it
+// has been, so the commands are drawn in order and in the drawing state they
+// were given in.
+func (ctx *Context2D) eval(script string, images []string) error {
+	c := ctx.canvas
+	if c.window == nil || c.window.started() == nil {
+		return fmt.Errorf("Canvas %s is not in a started Window", c.ID)
+	}
+	if images == nil {
+		images = []string{}
+	}
+	args, err := jsArgs(c.ID, images)
+	if err != nil {
+		return err
+	}
+	return c.window.started().Eval(fmt.Sprintf(`dali.draw(%s,function(ctx){%s})`, args, script)).Err()
+}
+
+// call invokes a method of the context
+func (ctx *Context2D) call(method string, args ...interface{}) error {
+	a, err := jsArgs(args...)
+	if err != nil {
+		return err
+	}
+	return ctx.run(fmt.Sprintf(`ctx.%s(%s);`, method, a))
+}
+
+// set assigns a property of the context
+func (ctx *Context2D) set(property string, value interface{}) error {
+	v, err := jsArgs(value)
+	if err != nil {
+		return err
+	}
+	return ctx.run(fmt.Sprintf(`ctx.%s=%s;`, property, v))
+}
+
+// BeginPath starts a new path
+func (ctx *Context2D) BeginPath() error { return ctx.call("beginPath") }
+
+// ClosePath draws a line back to the start of the current sub-path
+func (ctx *Context2D) ClosePath() error { return ctx.call("closePath") }
+
+// MoveTo begins a new sub-path at (x, y)
+func (ctx *Context2D) MoveTo(x, y float64) error { return ctx.call("moveTo", x, y) }
+
+// LineTo adds a straight line to (x, y) to the current sub-path
+func (ctx *Context2D) LineTo(x, y float64) error { return ctx.call("lineTo", x, y) }
+
+// Arc adds a circular arc centered on (x, y) to the current sub-path.  Angles are in radians.
+func (ctx *Context2D) Arc(x, y, radius, startAngle, endAngle float64, counterClockwise bool) error {
+	return ctx.call("arc", x, y, radius, startAngle, endAngle, counterClockwise)
+}
+
+// ArcTo adds an arc between two tangents to the current sub-path
+func (ctx *Context2D) ArcTo(x1, y1, x2, y2, radius float64) error {
+	return ctx.call("arcTo", x1, y1, x2, y2, radius)
+}
+
+// QuadraticCurveTo adds a quadratic Bézier curve to the current sub-path
+func (ctx *Context2D) QuadraticCurveTo(cpx, cpy, x, y float64) error {
+	return ctx.call("quadraticCurveTo", cpx, cpy, x, y)
+}
+
+// BezierCurveTo adds a cubic Bézier curve to the current sub-path
+func (ctx *Context2D) BezierCurveTo(cp1x, cp1y, cp2x, cp2y, x, y float64) error {
+	return ctx.call("bezierCurveTo", cp1x, cp1y, cp2x, cp2y, x, y)
+}
+
+// Rect adds a rectangle to the current path
+func (ctx *Context2D) Rect(x, y, width, height float64) error {
+	return ctx.call("rect", x, y, width, height)
+}
+
+// Stroke outlines the current path with the stroke style
+func (ctx *Context2D) Stroke() error { return ctx.call("stroke") }
+
+// Fill fills the current path with the fill style
+func (ctx *Context2D) Fill() error { return ctx.call("fill") }
+
+// Clip turns the current path into the clipping region
+func (ctx *Context2D) Clip() error { return ctx.call("clip") }
+
+// FillRect draws a filled rectangle
+func (ctx *Context2D) FillRect(x, y, width, height float64) error {
+	return ctx.call("fillRect", x, y, width, height)
+}
+
+// StrokeRect draws an outlined rectangle
+func (ctx *Context2D) StrokeRect(x, y, width, height float64) error {
+	return ctx.call("strokeRect", x, y, width, height)
+}
+
+// ClearRect erases a rectangle to transparent black
+func (ctx *Context2D) ClearRect(x, y, width, height float64) error {
+	return ctx.call("clearRect", x, y, width, height)
+}
+
+// StrokeStyle sets the CSS color used for lines and outlines
+func (ctx *Context2D) StrokeStyle(style string) error { return ctx.set("strokeStyle", style) }
+
+// FillStyle sets the CSS color used to fill shapes and text
+func (ctx *Context2D) FillStyle(style string) error { return ctx.set("fillStyle", style) }
+
+// LineWidth sets the thickness of lines
+func (ctx *Context2D) LineWidth(width float64) error { return ctx.set("lineWidth", width) }
+
+// LineCap sets how line ends are drawn: butt, round or square
+func (ctx *Context2D) LineCap(lineCap string) error { return ctx.set("lineCap", lineCap) }
+
+// LineJoin sets how corners are drawn: round, bevel or miter
+func (ctx *Context2D) LineJoin(lineJoin string) error { return ctx.set("lineJoin", lineJoin) }
+
+// GlobalAlpha sets the transparency applied to everything drawn, from 0 to 1
+func (ctx *Context2D) GlobalAlpha(alpha float64) error { return ctx.set("globalAlpha", alpha) }
+
+// Font sets the CSS font used for text
+func (ctx *Context2D) Font(font string) error { return ctx.set("font", font) }
+
+// TextAlign sets the horizontal alignment of text: start, end, left, right or center
+func (ctx *Context2D) TextAlign(align string) error { return ctx.set("textAlign", align) }
+
+// TextBaseline sets the vertical alignment of text: top, hanging, middle, alphabetic, ideographic or bottom
+func (ctx *Context2D) TextBaseline(baseline string) error { return ctx.set("textBaseline", baseline) }
+
+// FillText draws filled text at (x, y)
+func (ctx *Context2D) FillText(text string, x, y float64) error {
+	return ctx.call("fillText", text, x, y)
+}
+
+// StrokeText draws outlined text at (x, y)
+func (ctx *Context2D) StrokeText(text string, x, y float64) error {
+	return ctx.call("strokeText", text, x, y)
+}
+
+// DrawImage loads the image at url and draws it with its top left corner at (x, y)
+func (ctx *Context2D) DrawImage(url string, x, y float64) error {
+	return ctx.drawImage(url, x, y)
+}
+
+// DrawImageScaled loads the image at url and draws it scaled into the given rectangle
+func (ctx *Context2D) DrawImageScaled(url string, x, y, width, height float64) error {
+	return ctx.drawImage(url, x, y, width, height)
+}
+
+// drawImage draws the image the page has loaded from url.  The browser fetches
+// images asynchronously, so dali.draw loads them before drawing anything.
+func (ctx *Context2D) drawImage(url string, dims ...interface{}) error {
+	src, err := jsArgs(url)
+	if err != nil {
+		return err
+	}
+	a, err := jsArgs(dims...)
+	if err != nil {
+		return err
+	}
+	return ctx.run(fmt.Sprintf(`ctx.drawImage(dali.images[%s],%s);`, src, a), url)
+}
+
+// Save pushes the current drawing state onto a stack
+func (ctx *Context2D) Save() error { return ctx.call("save") }
+
+// Restore pops the most recently saved drawing state
+func (ctx *Context2D) Restore() error { return ctx.call("restore") }
+
+// Translate moves the origin by (x, y)
+func (ctx *Context2D) Translate(x, y float64) error { return ctx.call("translate", x, y) }
+
+// Rotate turns the coordinate system clockwise by angle radians
+func (ctx *Context2D) Rotate(angle float64) error { return ctx.call("rotate", angle) }
+
+// Scale stretches the coordinate system by x horizontally and y vertically
+func (ctx *Context2D) Scale(x, y float64) error { return ctx.call("scale", x, y) }
+
+// Transform multiplies the current transformation by the matrix [a c e; b d f; 0 0 1]
+func (ctx *Context2D) Transform(a, b, c, d, e, f float64) error {
+	return ctx.call("transform", a, b, c, d, e, f)
+}
+
+// SetTransform replaces the current transformation with the matrix [a c e; b d f; 0 0 1]
+func (ctx *Context2D) SetTransform(a, b, c, d, e, f float64) error {
+	return ctx.call("setTransform", a, b, c, d, e, f)
+}
+
+// ResetTransform restores the identity transformation
+func (ctx *Context2D) ResetTransform() error { return ctx.call("resetTransform") }
@@ -0,0 +1,134 @@
+package dali
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/zserge/lorca"
+)
+
+// fakeUI stands in for Chrome: each Eval takes delay, as a round trip to the
+// page does, and fails while err is set.  While found is set the scripts
+// report finding the nodes they change.
+type fakeUI struct {
+	lorca.UI
+	delay time.Duration
+	err   error
+	found bool
+	evals []string
+	lock  sync.Mutex
+}
+
+// foundValue is what a script returns when it finds the nodes it changes
+type foundValue struct{ errorValue }
+
+func (foundValue) Bool() bool { return true }
+
+func (u *fakeUI) Eval(js string) lorca.Value {
+	time.Sleep(u.delay)
+	u.lock.Lock()
+	defer u.lock.Unlock()
+	if u.err == nil {
+		u.evals = append(u.evals, js)
+	}
+	if u.found && u.err == nil {
+		return foundValue{}
+	}
+	return errorValue{u.err}
+}
+
+func (u *fakeUI) Bind(name string, f interface{}) error { return nil }
+
+// startedWindow is a Window showing els, running on ui
+func startedWindow(t testing.TB, ui lorca.UI, els ...Element) *Window {
+	w := NewWindow(100, 100, "", "")
+	for _, el := range els {
+		w.Elements.AddElement(el)
+		if err := start(el); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.run(ui, w.String()); err != nil {
+		t.Fatal(err)
+	}
+	return w
+}
+
+// startedCanvas is a Canvas in a Window running on ui
+func startedCanvas(ui lorca.UI) *Canvas {
+	w := NewWindow(100, 100, "", "")
+	w.ui = ui
+	c := NewCanvas(100, 100, "canvas")
+	w.attach(c)
+	return c
+}
+
+func TestFlushKeepsCommandsOnError(t *testing.T) {
+	ui := &fakeUI{err: fmt.Errorf("page is gone")}
+	ctx := startedCanvas(ui).Record()
+	ctx.MoveTo(0, 0)
+	ctx.LineTo(10, 10)
+	if err := ctx.Flush(); err == nil {
+		t.Errorf("expected the flush to fail")
+	}
+	if ctx.Len() != 2 {
+		t.Errorf(`expected 2 commands kept but got %d`, ctx.Len())
+	}
+
+	ui.err = nil
+	if err := ctx.Flush(); err != nil {
+		t.Errorf(`expected no error but got "%s"`, err)
+	}
+	if ctx.Len() != 0 || len(ui.evals) != 1 {
+		t.Errorf(`expected 0 commands in 1 Eval but got %d in %d`, ctx.Len(), len(ui.evals))
+	}
+}
+
+// drawLines draws a hundred lines on ctx
+func drawLines(ctx *Context2D) {
+	for i := 0; i < 100; i++ {
+		ctx.BeginPath()
+		ctx.MoveTo(float64(i), 0)
+		ctx.LineTo(0, float64(i))
+		ctx.Stroke()
+	}
+}
+
+// roundTrip is about as long as an Eval takes to reach Chrome and come back
+const roundTrip = 20 * time.Microsecond
+
+func BenchmarkImmediateDrawing(b *testing.B) {
+	ctx := startedCanvas(&fakeUI{delay: roundTrip}).Context()
+	for n := 0; n < b.N; n++ {
+		drawLines(ctx)
+	}
+}
+
+func BenchmarkRecordedDrawing(b *testing.B) {
+	ctx := startedCanvas(&fakeUI{delay: roundTrip}).Record()
+	for n := 0; n < b.N; n++ {
+		drawLines(ctx)
+		if err := ctx.Flush(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestFlushLoadsImagesBeforeDrawing(t *testing.T) {
+	ui := &fakeUI{}
+	ctx := startedCanvas(ui).Record()
+	ctx.Save()
+	ctx.Translate(10, 10)
+	ctx.DrawImage("a.png", 1, 2)
+	ctx.Restore()
+	ctx.FillRect(0, 0, 5, 5)
+	if err := ctx.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	expected := `dali.draw("canvas",["a.png"],function(ctx){ctx.save();ctx.translate(10,10);ctx.drawImage(dali.images["a.png"],1,2);ctx.restore();ctx.fillRect(0,0,5,5);})`
+	if len(ui.evals) != 1 || ui.evals[0] != expected {
+		t.Errorf(`expected "%s" but got %v`, expected, ui.evals)
+	}
+}
@@ -0,0 +1,478 @@
+package dali
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Px is a length in pixels
+func Px(n float64) string { return length(n, "px") }
+
+// Em is a length relative to the font size of the element
+func Em(n float64) string { return length(n, "em") }
+
+// Rem is a length relative to the font size of the page
+func Rem(n float64) string { return length(n, "rem") }
+
+// Percent is a length relative to the containing block
+func Percent(n float64) string { return length(n, "%") }
+
+// length renders n in unit, leaving out the unit for zero
+func length(n float64, unit string) string {
+	if n == 0 {
+		return "0"
+	}
+	return strconv.FormatFloat(n, 'f', -1, 64) + unit
+}
+
+// scopeCount numbers the class names made by ScopedClass
+var scopeCount uint64
+
+// ScopedClass returns a class name starting with name that no other call
+// returns, so the rules of one component never apply to another
+func ScopedClass(name string) string {
+	return fmt.Sprintf("%s-%d", name, atomic.AddUint64(&scopeCount, 1))
+}
+
+// Rule is a CSS rule: a selector, the declarations for what it selects, and
+// the media query it applies under, if any.  Rules are made by a CSS and their
+// methods return the Rule so declarations can be chained.
+type Rule struct {
+	Selector   string
+	MediaQuery string
+	styles     Styles
+	css        *CSS
+	err        error
+}
+
+// Set declares a property of the Rule, or removes it if value is empty.  A
+// declaration that would end the Rule or add others to it, such as a value
+// holding a ; or a }, is refused and left out: see Err.
+func (r *Rule) Set(property, value string) *Rule {
+	r.css.lock.Lock()
+	defer r.css.lock.Unlock()
+	if err := checkDeclaration(property, value); err != nil {
+		if r.err == nil {
+			r.err = err
+		}
+		return r
+	}
+	if value == "" {
+		delete(r.styles, property)
+	} else {
+		r.styles[property] = value
+	}
+	return r
+}
+
+// Err is the first declaration Set refused, if any
+func (r *Rule) Err() error {
+	r.css.lock.Lock()
+	defer r.css.lock.Unlock()
+	return r.err
+}
+
+// cssProperty matches property names, custom properties such as --accent included
+var cssProperty = regexp.MustCompile(`^-{0,2}[A-Za-z_][-A-Za-z0-9_]*$`)
+
+// checkDeclaration returns an error unless property: value stays within its
+// declaration: the property is a name, and the value is enclosed
+func checkDeclaration(property, value string) error {
+	if !cssProperty.MatchString(property) {
+		return fmt.Errorf("%q is not a CSS property", property)
+	}
+	if err := checkEnclosed(value); err != nil {
+		return fmt.Errorf("the value of %s %v: %q", property, err, value)
+	}
+	return nil
+}
+
+// checkEnclosed returns an error unless s stays within the rule it is part
+// of: any ; { or } is inside quotes, parentheses or brackets, as in
+// url(data:image/png;base64,...), every one of them is closed, and no comment starts
+func checkEnclosed(s string) error {
+	depth := 0
+	var quote, last rune
+	escaped := false
+	for _, c := range s {
+		switch {
+		case escaped:
+			escaped = false
+		case c == '\\':
+			escaped = true
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '(' || c == '[':
+			depth++
+		case c == ')' || c == ']':
+			depth--
+		case c == '*' && last == '/':
+			return fmt.Errorf("may not hold a comment")
+		case c == ';' || c == '{' || c == '}':
+			if depth == 0 {
+				return fmt.Errorf("may not hold %q", c)
+			}
+		}
+		last = c
+		if depth < 0 {
+			break
+		}
+	}
+	if depth != 0 || quote != 0 || escaped {
+		return fmt.Errorf("is not closed")
+	}
+	return nil
+}
+
+// SetStyles declares every property of styles
+func (r *Rule) SetStyles(styles Styles) *Rule {
+	for property, value := range styles {
+		r.Set(property, value)
+	}
+	return r
+}
+
+// Styles are the declarations of the Rule
+func (r *Rule) Styles() Styles {
+	r.css.lock.Lock()
+	defer r.css.lock.Unlock()
+	styles := Styles{}
+	for k, v := range r.styles {
+		styles[k] = v
+	}
+	return styles
+}
+
+// Color sets the text color
+func (r *Rule) Color(color string) *Rule { return r.Set("color", color) }
+
+// Background sets the background
+func (r *Rule) Background(background string) *Rule { return r.Set("background", background) }
+
+// Display sets how the element is laid out, such as block, flex or none
+func (r *Rule) Display(display string) *Rule { return r.Set("display", display) }
+
+// Width sets the width
+func (r *Rule) Width(width string) *Rule { return r.Set("width", width) }
+
+// Height sets the height
+func (r *Rule) Height(height string) *Rule { return r.Set("height", height) }
+
+// Margin sets the margin
+func (r *Rule) Margin(margin string) *Rule { return r.Set("margin", margin) }
+
+// Padding sets the padding
+func (r *Rule) Padding(padding string) *Rule { return r.Set("padding", padding) }
+
+// Border sets the border
+func (r *Rule) Border(border string) *Rule { return r.Set("border", border) }
+
+// Font sets the font
+func (r *Rule) Font(font string) *Rule { return r.Set("font", font) }
+
+// FontSize sets the font size
+func (r *Rule) FontSize(size string) *Rule { return r.Set("font-size", size) }
+
+// cssPseudo matches a pseudo-class, with its arguments if it has any, or a pseudo-element after a :
+var cssPseudo = regexp.MustCompile(`^:?[A-Za-z][-A-Za-z0-9]*(\(.*\))?$`)
+
+// Pseudo adds a rule for the pseudo-class of what the Rule selects, such as
+// hover or nth-child(2n).  A class that is not one, such as hover,body, is
+// refused: see Err.
+func (r *Rule) Pseudo(class string) *Rule {
+	if !cssPseudo.MatchString(class) {
+		return r.css.refused(r.Selector+":"+class, r.MediaQuery, fmt.Errorf("%q is not a pseudo-class", class))
+	}
+	return r.css.add(mapSelectors(r.Selector, func(s string) string { return s + ":" + class }), r.MediaQuery)
+}
+
+// Hover adds a rule for what the Rule selects while the pointer is over it
+func (r *Rule) Hover() *Rule { return r.Pseudo("hover") }
+
+// Focus adds a rule for what the Rule selects while it has the focus
+func (r *Rule) Focus() *Rule { return r.Pseudo("focus") }
+
+// Active adds a rule for what the Rule selects while it is being clicked
+func (r *Rule) Active() *Rule { return r.Pseudo("active") }
+
+// Disabled adds a rule for what the Rule selects while it is disabled
+func (r *Rule) Disabled() *Rule { return r.Pseudo("disabled") }
+
+// Descendant adds a rule for the elements matching selector inside what the
+// Rule selects.  Each selector of a list is looked for inside each of the Rule.
+func (r *Rule) Descendant(selector string) *Rule {
+	if err := checkSelector(selector); err != nil {
+		return r.css.refused(r.Selector+" "+selector, r.MediaQuery, err)
+	}
+	return r.css.add(mapSelectors(r.Selector, func(s string) string {
+		return mapSelectors(selector, func(d string) string { return s + " " + d })
+	}), r.MediaQuery)
+}
+
+// Media adds a rule for the same selector that applies under query, such as (max-width: 600px)
+func (r *Rule) Media(query string) *Rule {
+	return r.css.add(r.Selector, query)
+}
+
+// ClassName is the class selected by a Rule made with CSS.Class, for AddClass
+func (r *Rule) ClassName() string {
+	if strings.HasPrefix(r.Selector, ".") && !strings.ContainsAny(r.Selector, " ,:>+~[#") {
+		return r.Selector[1:]
+	}
+	return ""
+}
+
+// String renders the Rule with its properties sorted, outside any media query
+func (r *Rule) String() string {
+	r.css.lock.Lock()
+	defer r.css.lock.Unlock()
+	return r.text()
+}
+
+// text renders the Rule, which must be locked
+func (r *Rule) text() string {
+	return fmt.Sprintf("%s{%s}", r.Selector, r.styles)
+}
+
+// mapSelectors applies f to each selector of a selector list
+func mapSelectors(selector string, f func(string) string) string {
+	parts := splitSelectors(selector)
+	for i, p := range parts {
+		parts[i] = f(p)
+	}
+	return strings.Join(parts, ",")
+}
+
+// splitSelectors splits a selector list at the commas outside quotes,
+// parentheses and brackets, so :is(a,b) stays whole
+func splitSelectors(selector string) []string {
+	parts := []string{}
+	depth, start := 0, 0
+	var quote rune
+	escaped := false
+	for i, c := range selector {
+		switch {
+		case escaped:
+			escaped = false
+		case c == '\\':
+			escaped = true
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '(' || c == '[':
+			depth++
+		case c == ')' || c == ']':
+			depth--
+		case c == ',' && depth == 0:
+			parts = append(parts, strings.TrimSpace(selector[start:i]))
+			start = i + 1
+		}
+	}
+	return append(parts, strings.TrimSpace(selector[start:]))
+}
+
+// checkSelector returns an error unless selector is one that stays within its rule
+func checkSelector(selector string) error {
+	for _, s := range splitSelectors(selector) {
+		if s == "" {
+			return fmt.Errorf("%q is not a selector", selector)
+		}
+	}
+	if err := checkEnclosed(selector); err != nil {
+		return fmt.Errorf("the selector %v: %q", err, selector)
+	}
+	return nil
+}
+
+// CSS is a stylesheet built from Rules.  It renders rules in the order they
+// were added, each with its properties sorted, so the same rules always give
+// the same text.  Added to a Window with AddCSS it goes in the document head.
+type CSS struct {
+	ID     string
+	rules  []*Rule
+	lock   sync.Mutex
+	window *Window
+	BaseElement
+}
+
+// NewCSS creates a CSS with no rules, rendered as a style element with the given id
+func NewCSS(name string) *CSS {
+	return &CSS{ID: name}
+}
+
+// Rule adds a rule for selector.  A selector that would end the rule, such as
+// one holding a { or }, is refused and the Rule left out of the CSS: see Err.
+func (c *CSS) Rule(selector string) *Rule {
+	return c.add(selector, "")
+}
+
+// Class adds a rule for a scoped class, named from name by ScopedClass.
+// Give the ClassName of the Rule to the AddClass of the elements it styles.
+func (c *CSS) Class(name string) *Rule {
+	return c.add("."+ScopedClass(name), "")
+}
+
+// Media adds a rule for selector that applies under query, such as
+// (prefers-color-scheme: dark).  A selector or query that would end the rule
+// is refused and the Rule left out of the CSS: see Err.
+func (c *CSS) Media(query, selector string) *Rule {
+	return c.add(selector, query)
+}
+
+// add adds a rule, unless its selector or query would end it
+func (c *CSS) add(selector, query string) *Rule {
+	if err := checkSelector(selector); err != nil {
+		return c.refused(selector, query, err)
+	}
+	if err := checkEnclosed(query); err != nil {
+		return c.refused(selector, query, fmt.Errorf("the media query %v: %q", err, query))
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	r := &Rule{Selector: selector, MediaQuery: query, styles: Styles{}, css: c}
+	c.rules = append(c.rules, r)
+	return r
+}
+
+// refused is a Rule left out of the CSS, whose Err says why
+func (c *CSS) refused(selector, query string, err error) *Rule {
+	return &Rule{Selector: selector, MediaQuery: query, styles: Styles{}, css: c, err: err}
+}
+
+// Remove takes a rule out of the CSS
+func (c *CSS) Remove(r *Rule) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for i, rule := range c.rules {
+		if rule == r {
+			c.rules = append(c.rules[:i], c.rules[i+1:]...)
+			return
+		}
+	}
+}
+
+// Text is the stylesheet: the rules outside media queries in order, then
+// the rules of each media query, grouped in the order the queries first appear
+func (c *CSS) Text() string {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	text := ""
+	queries := []string{}
+	media := map[string]string{}
+	for _, r := range c.rules {
+		if len(r.styles) == 0 {
+			continue
+		}
+		if r.MediaQuery == "" {
+			text += r.text()
+			continue
+		}
+		if _, ok := media[r.MediaQuery]; !ok {
+			queries = append(queries, r.MediaQuery)
+		}
+		media[r.MediaQuery] += r.text()
+	}
+	for _, q := range queries {
+		text += fmt.Sprintf("@media %s{%s}", q, media[q])
+	}
+	// a stylesheet must not end the style element it is rendered in
+	return strings.Replace(text, "</", `<\/`, -1)
+}
+
+// String for CSS
+func (c *CSS) String() string {
+	id := ""
+	if c.ID != "" {
+		id = attribute("id", c.ID)
+	}
+	return fmt.Sprintf(`<style%s%s>%s</style>`, id, c.attributes(), c.Text())
+}
+
+// Name of the CSS
+func (c *CSS) Name() string { return c.ID }
+
+// Refresh brings the page up to date with the rules after they have changed
+func (c *CSS) Refresh() error {
+	if c.window == nil || c.window.started() == nil {
+		return nil
+	}
+	return c.window.Update()
+}
+
+// AddCSS puts css in the document head.  Once the Window is running it is
+// added to the page straight away.
+func (w *Window) AddCSS(css *CSS) error {
+	for _, c := range w.css {
+		if c == css {
+			return nil
+		}
+	}
+	css.window = w
+	w.css = append(w.css, css)
+	if w.started() == nil {
+		return nil
+	}
+	return w.Update()
+}
+
+// RemoveCSS takes css out of the document head
+func (w *Window) RemoveCSS(css *CSS) error {
+	for i, c := range w.css {
+		if c == css {
+			w.css = append(w.css[:i], w.css[i+1:]...)
+			css.window = nil
+			if w.started() == nil {
+				return nil
+			}
+			return w.Update()
+		}
+	}
+	return nil
+}
+
+// layoutCSS collects the rules of the layout containers among the elements of the Window
+func (w *Window) layoutCSS() string {
+	css := NewCSS("dali-layout")
+	var walk func(els *Elements)
+	walk = func(els *Elements) {
+		if els == nil {
+			return
+		}
+		for _, el := range els.slice {
+			if s, ok := (*el).(styled); ok {
+				s.rules(css)
+			}
+			walk((*el).Children())
+		}
+	}
+	walk(w.Elements)
+	if css.Text() == "" {
+		return ""
+	}
+	return css.String()
+}
+
+// head renders what the Window adds to the document head: the dali runtime,
+// its stylesheets, its themes, its CSS, then the rules of its layout containers
+func (w *Window) head() string {
+	head := fmt.Sprintf(`<script id="dali-runtime">%s</script>`, runtimeScript) + w.Style.String()
+	for _, s := range w.stylesheets {
+		head += s.String()
+	}
+	head += w.themeCSS()
+	for _, c := range w.css {
+		head += c.String()
+	}
+	return head + w.layoutCSS()
+}
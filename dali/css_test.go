@@ -0,0 +1,54 @@
+package dali
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestRuleSetRefusesInjection(t *testing.T) {
+	css := NewCSS("test")
+	r := css.Rule(".card").Color("red").
+		Set("background", "url(data:image/png;base64,AAAA)").
+		Set("content", `"a;b}"`).
+		Set("width", "1px}body{display:none").
+		Set("margin", "0;position:fixed")
+	expected := `.card{background:url(data:image/png;base64,AAAA);color:red;content:"a;b}";}`
+	if html := fmt.Sprintf("%s", r); html != expected {
+		t.Errorf(`expected "%s" but got "%s"`, expected, html)
+	}
+	if r.Err() == nil {
+		t.Errorf("expected the declarations breaking out of the rule to be refused")
+	}
+}
+
+func TestSelectorsRefuseInjection(t *testing.T) {
+	css := NewCSS("test")
+	refused := []*Rule{
+		css.Rule("a{}body"),
+		css.Rule("a/*"),
+		css.Rule(`a[title="x]`),
+		css.Rule(""),
+		css.Media("screen{}body{", "a"),
+		css.Rule(".card").Pseudo("hover,body"),
+		css.Rule(".card").Pseudo("hover{}body"),
+		css.Rule(".card").Descendant("p}body{"),
+	}
+	for i, r := range refused {
+		if r.Err() == nil {
+			t.Errorf(`expected rule %d, "%s", to be refused`, i, r.Selector)
+		}
+	}
+	if text := css.Text(); text != "" {
+		t.Errorf(`expected the refused rules to be left out but got "%s"`, text)
+	}
+}
+
+func TestSelectorLists(t *testing.T) {
+	css := NewCSS("test")
+	css.Rule(".card, :is(h1,h2)").Pseudo("nth-child(2n + 1)").Color("red")
+	css.Rule(".card").Descendant("p, span").Color("blue")
+	expected := `.card:nth-child(2n + 1),:is(h1,h2):nth-child(2n + 1){color:red;}.card p,.card span{color:blue;}`
+	if text := css.Text(); text != expected {
+		t.Errorf(`expected "%s" but got "%s"`, expected, text)
+	}
+}
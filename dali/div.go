@@ -0,0 +1,73 @@
+package dali
+
+import "fmt"
+
+// Div is a page within a Window
+type Div struct {
+	ID        string
+	StyleName string
+	Elements  *Elements
+	BaseElement
+	Binding
+}
+
+// Bindings returns the binding
+func (p *Div) Bindings() *Binding { return &p.Binding }
+
+//Children returns the Elements
+func (p *Div) Children() *Elements { return p.Elements }
+
+//String for Div
+func (p *Div) String() string {
+	style := ""
+	if p.StyleName != "" {
+		style = attribute("style", p.StyleName)
+	}
+
+	return fmt.Sprintf(`<div id="%s"%s%s%s>%s</div>`, escape(p.Name()), style, p.attributes(), p.Events.attributes(), p.Elements)
+}
+
+// NewDiv generates a new Div
+func NewDiv(name string) *Div {
+	els := Elements{slice: []*Element{}}
+	return &Div{
+		ID:       name,
+		Elements: &els,
+	}
+}
+
+//Name returns the name of the Div
+func (p *Div) Name() string {
+	return p.ID
+}
+
+//Style of the Div
+func (p *Div) Style() string { return p.StyleName }
+
+//Styles of the Div
+func (p *Div) Styles() Styles { return parseStyles(p.StyleName) }
+
+// SetText replaces the contents of the Div with text
+func (p *Div) SetText(text string) error {
+	var t Element = Text(text)
+	p.Elements.slice = []*Element{&t}
+	return p.live.setHTML(p.Elements.String())
+}
+
+// SetHTML replaces the contents of the Div with markup
+func (p *Div) SetHTML(html string) error {
+	var t Element = RawHTML(html)
+	p.Elements.slice = []*Element{&t}
+	return p.live.setHTML(p.Elements.String())
+}
+
+// SetStyle sets a CSS property of the Div, or removes it if value is empty
+func (p *Div) SetStyle(property, value string) error {
+	return p.live.setStyle(&p.StyleName, property, value)
+}
+
+// Show displays the Div
+func (p *Div) Show() error { return p.live.show(&p.StyleName) }
+
+// Hide hides the Div
+func (p *Div) Hide() error { return p.live.hide(&p.StyleName) }
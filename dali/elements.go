@@ -0,0 +1,293 @@
+package dali
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// Len is the number of elements
+func (els *Elements) Len() int {
+	if els == nil {
+		return 0
+	}
+	return len(els.slice)
+}
+
+// Get returns the element at index i
+func (els *Elements) Get(i int) Element { return *els.slice[i] }
+
+// running is true once the Window showing the elements has started
+func (els *Elements) running() bool {
+	return els.window != nil && els.window.started() != nil
+}
+
+// markerCount numbers the markers of elements across every Elements
+var markerCount uint64
+
+// endMarker is the marker after the last element of an Elements
+const endMarker = "/dali"
+
+// markers returns the marker of each element, numbering the new ones and
+// forgetting the ones of elements that are gone
+func (els *Elements) markers() []string {
+	els.keysLock.Lock()
+	defer els.keysLock.Unlock()
+	keys := make(map[*Element]string, len(els.slice))
+	markers := make([]string, len(els.slice))
+	for i, el := range els.slice {
+		k, ok := els.keys[el]
+		if !ok {
+			k = fmt.Sprintf("dali:%d", atomic.AddUint64(&markerCount, 1))
+		}
+		keys[el] = k
+		markers[i] = k
+	}
+	els.keys = keys
+	return markers
+}
+
+// key returns the marker of el, numbering it if it has none yet
+func (els *Elements) key(el *Element) string {
+	els.keysLock.Lock()
+	defer els.keysLock.Unlock()
+	if els.keys == nil {
+		els.keys = map[*Element]string{}
+	}
+	k, ok := els.keys[el]
+	if !ok {
+		k = fmt.Sprintf("dali:%d", atomic.AddUint64(&markerCount, 1))
+		els.keys[el] = k
+	}
+	return k
+}
+
+// marked renders el after its marker
+func (els *Elements) marked(el *Element) string {
+	return fmt.Sprintf(`<!--%s-->%s`, els.key(el), *el)
+}
+
+// Insert adds e at index i, moving the elements from i on along by one
+func (els *Elements) Insert(i int, e Element) error {
+	if i < 0 || i > len(els.slice) {
+		return fmt.Errorf("cannot insert at %d in %d elements", i, len(els.slice))
+	}
+	before := endMarker
+	if i < len(els.slice) {
+		before = els.key(els.slice[i])
+	}
+	els.slice = append(els.slice, nil)
+	copy(els.slice[i+1:], els.slice[i:])
+	els.slice[i] = &e
+
+	if !els.running() {
+		return nil
+	}
+	if err := start(e); err != nil {
+		return err
+	}
+	if hasRules(e) {
+		return els.window.Update()
+	}
+	html := els.marked(els.slice[i])
+	if err := els.change(func(t *tree, p *vnode) {
+		if at, _ := markedNodes(p, before); at >= 0 {
+			t.splice(p, at, 0, parseHTML(html)...)
+		}
+	}, `return dali.insert(p,%s,%s);`, before, html); err != nil {
+		return err
+	}
+	return els.adopt(e)
+}
+
+// Remove takes the element at index i out
+func (els *Elements) Remove(i int) error {
+	if i < 0 || i >= len(els.slice) {
+		return fmt.Errorf("cannot remove %d of %d elements", i, len(els.slice))
+	}
+	key := els.key(els.slice[i])
+	els.slice = append(els.slice[:i], els.slice[i+1:]...)
+
+	if !els.running() {
+		return nil
+	}
+	return els.change(func(t *tree, p *vnode) {
+		if start, end := markedNodes(p, key); start >= 0 {
+			t.splice(p, start, end-start)
+		}
+	}, `return dali.remove(p,%s);`, key)
+}
+
+// Replace puts e in place of the element at index i
+func (els *Elements) Replace(i int, e Element) error {
+	if i < 0 || i >= len(els.slice) {
+		return fmt.Errorf("cannot replace %d of %d elements", i, len(els.slice))
+	}
+	key := els.key(els.slice[i])
+	els.slice[i] = &e
+
+	if !els.running() {
+		return nil
+	}
+	if err := start(e); err != nil {
+		return err
+	}
+	if hasRules(e) {
+		return els.window.Update()
+	}
+	html := els.marked(els.slice[i])
+	if err := els.change(func(t *tree, p *vnode) {
+		if start, end := markedNodes(p, key); start >= 0 {
+			t.splice(p, start, end-start, parseHTML(html)...)
+		}
+	}, `return dali.replace(p,%s,%s);`, key, html); err != nil {
+		return err
+	}
+	return els.adopt(e)
+}
+
+// Move takes the element at index from and puts it at index to
+func (els *Elements) Move(from, to int) error {
+	if from < 0 || from >= len(els.slice) || to < 0 || to >= len(els.slice) {
+		return fmt.Errorf("cannot move %d to %d in %d elements", from, to, len(els.slice))
+	}
+	e := els.slice[from]
+	key := els.key(e)
+	els.slice = append(els.slice[:from], els.slice[from+1:]...)
+	before := endMarker
+	if to < len(els.slice) {
+		before = els.key(els.slice[to])
+	}
+	els.slice = append(els.slice, nil)
+	copy(els.slice[to+1:], els.slice[to:])
+	els.slice[to] = e
+
+	if !els.running() {
+		return nil
+	}
+	return els.change(func(t *tree, p *vnode) {
+		start, end := markedNodes(p, key)
+		if start < 0 {
+			return
+		}
+		nodes := append([]*vnode{}, p.children[start:end]...)
+		t.splice(p, start, end-start)
+		if at, _ := markedNodes(p, before); at >= 0 {
+			t.splice(p, at, 0, nodes...)
+		}
+	}, `return dali.move(p,%s,%s);`, key, before)
+}
+
+// Clear removes every element
+func (els *Elements) Clear() error {
+	els.slice = []*Element{}
+
+	if !els.running() {
+		return nil
+	}
+	return els.change(func(t *tree, p *vnode) {
+		end, _ := markedNodes(p, endMarker)
+		start := 0
+		for start < end && !isMarker(p.children[start]) {
+			start++
+		}
+		if end >= 0 {
+			t.splice(p, start, end-start)
+		}
+	}, `return dali.clear(p);`)
+}
+
+// adopt attaches an element added to a running page and binds its functions
+func (els *Elements) adopt(e Element) error {
+	w := els.window
+	w.updating.Lock()
+	defer w.updating.Unlock()
+	w.attach(e)
+	w.bindChildren(&e)
+	return w.bindPending()
+}
+
+// hasRules is true when e or an element below it has rules for the document
+// head: it is added with an Update, so the page gets its rules along with it
+func hasRules(e Element) bool {
+	if _, ok := e.(styled); ok {
+		return true
+	}
+	if children := e.Children(); children != nil {
+		for _, c := range children.slice {
+			if hasRules(*c) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// change runs script in the page with p bound to the node of the owner of the
+// elements, then makes the same change with mirror to that node in the tree
+// the page was sent.  Each %s in the script is replaced by the matching
+// argument as a JavaScript literal.  The script returns false when it cannot
+// find the marked nodes it changes, and the whole page is updated instead, as
+// it is for the elements of the Window itself.
+func (els *Elements) change(mirror func(t *tree, p *vnode), script string, args ...interface{}) error {
+	w := els.window
+	if els.owner == nil || els.owner.Name() == "" {
+		return w.Update()
+	}
+	id, err := jsArgs(els.owner.Name())
+	if err != nil {
+		return err
+	}
+	literals := make([]interface{}, len(args))
+	for i, arg := range args {
+		if literals[i], err = jsArgs(arg); err != nil {
+			return err
+		}
+	}
+	w.lock.Lock()
+	v := w.started().Eval(fmt.Sprintf(`(function(p){%s})(document.getElementById(%s))`, fmt.Sprintf(script, literals...), id))
+	if v.Err() != nil {
+		w.lock.Unlock()
+		return v.Err()
+	}
+	if !v.Bool() {
+		w.lock.Unlock()
+		return w.Update()
+	}
+	if w.rendered != nil {
+		if p := w.rendered.ids[els.owner.Name()]; p != nil {
+			mirror(w.rendered, p)
+		}
+	}
+	w.lock.Unlock()
+	return nil
+}
+
+// isMarker is true for the comment put before an element of an Elements, or after the last
+func isMarker(n *vnode) bool {
+	return n.tag == commentTag && (strings.HasPrefix(n.text, "dali:") || n.text == endMarker)
+}
+
+// markedNodes finds the children of p from the marker key up to the next
+// marker, as dali.marked does in the page.  start is -1 if there is no such marker.
+func markedNodes(p *vnode, key string) (start, end int) {
+	start = -1
+	for i, c := range p.children {
+		if !isMarker(c) {
+			continue
+		}
+		if start >= 0 {
+			return start, i
+		}
+		if c.text == key {
+			start = i
+			if key == endMarker {
+				return start, i + 1
+			}
+		}
+	}
+	if start < 0 {
+		return -1, -1
+	}
+	return start, len(p.children)
+}
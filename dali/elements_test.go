@@ -0,0 +1,74 @@
+package dali
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// inStep fails t unless the tree the page was sent matches the Window rendered now
+func inStep(t *testing.T, w *Window, step string) {
+	t.Helper()
+	if patches := diff(w.rendered.root, normalizeDocument(parseHTML(w.String())), []int{}, []patch{}); len(patches) > 0 {
+		t.Errorf("after %s expected the page to match the Window but it differs by %+v", step, patches)
+	}
+}
+
+func TestElementsChangeTheMarkedNodes(t *testing.T) {
+	panel := NewDiv("panel")
+	ui := &fakeUI{found: true}
+	w := startedWindow(t, ui, panel)
+	span := func(text string) Element { return &Span{ID: text, Text: text} }
+
+	steps := []struct {
+		name     string
+		change   func() error
+		expected string
+	}{
+		{"inserting a", func() error { return panel.Elements.Insert(0, span("a")) }, "a"},
+		{"inserting b", func() error { return panel.Elements.Insert(1, span("b")) }, "ab"},
+		{"inserting c between", func() error { return panel.Elements.Insert(1, span("c")) }, "acb"},
+		{"moving a to the end", func() error { return panel.Elements.Move(0, 2) }, "cba"},
+		{"moving a to the start", func() error { return panel.Elements.Move(2, 0) }, "acb"},
+		{"replacing c", func() error { return panel.Elements.Replace(1, span("d")) }, "adb"},
+		{"removing a", func() error { return panel.Elements.Remove(0) }, "db"},
+		{"removing b", func() error { return panel.Elements.Remove(1) }, "d"},
+		{"clearing", panel.Elements.Clear, ""},
+		{"inserting after clearing", func() error { return panel.Elements.Insert(0, span("e")) }, "e"},
+	}
+	for _, step := range steps {
+		if err := step.change(); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		shown := ""
+		for i := 0; i < panel.Elements.Len(); i++ {
+			shown += panel.Elements.Get(i).(*Span).Text
+		}
+		if shown != step.expected {
+			t.Errorf(`after %s expected "%s" but got "%s"`, step.name, step.expected, shown)
+		}
+		inStep(t, w, step.name)
+	}
+	for _, js := range ui.evals {
+		if strings.Contains(js, "dali.patch") {
+			t.Errorf(`expected each change to be made in place but the page was updated with "%s"`, js)
+		}
+	}
+}
+
+func TestElementsOutOfRange(t *testing.T) {
+	els := &Elements{slice: []*Element{}}
+	for name, err := range map[string]error{
+		"insert":  els.Insert(1, Text("a")),
+		"remove":  els.Remove(0),
+		"replace": els.Replace(0, Text("a")),
+		"move":    els.Move(0, 1),
+	} {
+		if err == nil {
+			t.Errorf("expected %s to fail on empty elements", name)
+		}
+	}
+	if fmt.Sprint(els) != "<!--/dali-->" {
+		t.Errorf(`expected "%s" but got "%s"`, "<!--/dali-->", els)
+	}
+}
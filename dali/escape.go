@@ -0,0 +1,65 @@
+package dali
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+// attributeName matches the attribute names dali is willing to render
+var attributeName = regexp.MustCompile(`^[A-Za-z_:][-A-Za-z0-9_:.]*$`)
+
+// escape makes text safe to render as the content of an element or the value of an attribute
+func escape(text string) string { return html.EscapeString(text) }
+
+// attribute renders ` name="value"` with the value escaped.  Names that are
+// not valid attribute names are not rendered at all, nor are URL attributes
+// whose URL safeURL rejects.
+func attribute(name, value string) string {
+	if !attributeName.MatchString(name) {
+		return ""
+	}
+	if urlAttributes[strings.ToLower(name)] && !safeURL(value) {
+		return ""
+	}
+	return fmt.Sprintf(` %s="%s"`, name, escape(value))
+}
+
+// urlAttributes are the attributes holding a URL the page loads or goes to
+var urlAttributes = map[string]bool{
+	"href": true, "src": true, "action": true, "formaction": true, "poster": true,
+	"cite": true, "background": true, "xlink:href": true,
+}
+
+// safeSchemes are the URL schemes allowed in a URL attribute
+var safeSchemes = map[string]bool{"http": true, "https": true, "mailto": true}
+
+// safeURL is true for a relative URL, a URL with a scheme in safeSchemes, or
+// a data URL of an image.  Others, such as javascript: URLs, could run script.
+func safeURL(value string) bool {
+	// the browser ignores whitespace and control characters in a scheme
+	u := strings.Map(func(r rune) rune {
+		if r <= ' ' {
+			return -1
+		}
+		return r
+	}, value)
+	colon := strings.IndexByte(u, ':')
+	if colon < 0 || strings.ContainsAny(u[:colon], "/?#") {
+		return true
+	}
+	scheme := strings.ToLower(u[:colon])
+	if scheme == "data" {
+		return strings.HasPrefix(strings.ToLower(u), "data:image/")
+	}
+	return safeSchemes[scheme]
+}
+
+// checkURL returns an error for a URL that safeURL rejects
+func checkURL(link string) error {
+	if !safeURL(link) {
+		return fmt.Errorf("%q is not a relative, http, https or mailto URL", link)
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package dali
+
+import "testing"
+
+func TestAttributeEscapes(t *testing.T) {
+	for _, c := range []struct{ name, value, expected string }{
+		{"title", `"><script>`, ` title="&#34;&gt;&lt;script&gt;"`},
+		{"onclick", `go('a')`, ` onclick="go(&#39;a&#39;)"`},
+		{`x" onload="`, "1", ``},
+		{"href", "#/users/1", ` href="#/users/1"`},
+		{"href", "https://example.com/?a=1&b=2", ` href="https://example.com/?a=1&amp;b=2"`},
+		{"href", "javascript:alert(1)", ``},
+		{"SRC", " java\tscript:alert(1)", ``},
+		{"src", "data:text/html,<script>", ``},
+		{"src", "data:image/png;base64,AAAA", ` src="data:image/png;base64,AAAA"`},
+	} {
+		if html := attribute(c.name, c.value); html != c.expected {
+			t.Errorf(`expected "%s" but got "%s"`, c.expected, html)
+		}
+	}
+}
+
+func TestSafeURL(t *testing.T) {
+	for url, expected := range map[string]bool{
+		"page.html":              true,
+		"/a:b":                   true,
+		"?q=a:b":                 true,
+		"mailto:ann@example.com": true,
+		"HTTP://example.com":     true,
+		"vbscript:msgbox":        false,
+		"file:///etc/passwd":     false,
+	} {
+		if safeURL(url) != expected {
+			t.Errorf(`expected safeURL("%s") to be %t`, url, expected)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package dali
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Event is a DOM event delivered to Go
+type Event struct {
+	Type    string  `json:"type"`
+	Target  string  `json:"target"` // id of the element the event happened on
+	Value   string  `json:"value"`  // value of the target, for inputs
+	Checked bool    `json:"checked"`
+	Key     string  `json:"key"`
+	X       float64 `json:"x"` // offset of the pointer within the target
+	Y       float64 `json:"y"`
+	Button  int     `json:"button"`
+	Alt     bool    `json:"alt"`
+	Ctrl    bool    `json:"ctrl"`
+	Shift   bool    `json:"shift"`
+	Meta    bool    `json:"meta"`
+}
+
+// eventMessage is an Event as the page sends it, numbered in the order the events happened
+type eventMessage struct {
+	Event
+	sequenced
+}
+
+// sequenced numbers a call from the page.  dali.send counts the calls to each
+// Go function from 1, and Page tells the page apart from the one before a reload.
+type sequenced struct {
+	Page uint64 `json:"page"`
+	Seq  uint64 `json:"seq"`
+}
+
+// sequenceGap is how long events wait for an earlier one that has not arrived
+// before they are applied without it
+var sequenceGap = 100 * time.Millisecond
+
+// sequencer applies the calls to one Go function one at a time, in the order
+// they were made.  lorca delivers each call from the page in a goroutine of its
+// own, so calls can arrive out of order; one that arrives early is held until
+// those before it have been applied.  No call is ever dropped: if one never
+// arrives, those after it are applied once they have waited sequenceGap, and
+// one arriving after that, or from a page since reloaded, is applied at once.
+type sequencer struct {
+	page  uint64
+	next  uint64
+	held  map[uint64]func()
+	timer *time.Timer
+	lock  sync.Mutex
+}
+
+// apply calls f once the calls numbered before n have been applied
+func (s *sequencer) apply(n sequenced, f func()) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if n.Page > s.page {
+		s.flush()
+		s.page, s.next = n.Page, 1
+	}
+	if n.Seq == 0 || n.Page < s.page || n.Seq < s.next {
+		f()
+		return
+	}
+	if s.held == nil {
+		s.held = map[uint64]func(){}
+	}
+	s.held[n.Seq] = f
+	s.release()
+}
+
+// release applies the held calls from next on, until one is missing, and
+// waits sequenceGap for the missing one if any are still held
+func (s *sequencer) release() {
+	for f, ok := s.held[s.next]; ok; f, ok = s.held[s.next] {
+		delete(s.held, s.next)
+		s.next++
+		f()
+	}
+	if len(s.held) == 0 && s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	if len(s.held) > 0 && s.timer == nil {
+		s.timer = time.AfterFunc(sequenceGap, s.skip)
+	}
+}
+
+// skip stops waiting for a missing call and applies those held after it
+func (s *sequencer) skip() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.timer = nil
+	if len(s.held) == 0 {
+		return
+	}
+	s.next = s.first()
+	s.release()
+}
+
+// flush applies every held call in order, as a reload leaves nothing to wait for
+func (s *sequencer) flush() {
+	for len(s.held) > 0 {
+		s.next = s.first()
+		s.release()
+	}
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+}
+
+// first is the lowest number held
+func (s *sequencer) first() uint64 {
+	first := uint64(0)
+	for seq := range s.held {
+		if first == 0 || seq < first {
+			first = seq
+		}
+	}
+	return first
+}
+
+// EventHandler handles an Event.  The value or error it returns resolves or
+// rejects the promise of the JavaScript call that delivered the event.
+type EventHandler func(Event) (interface{}, error)
+
+// Events binds DOM events of an element to Go handlers.  Elements embed it to provide On.
+type Events struct {
+	bound map[string]eventBinding
+}
+
+// eventBinding is the JavaScript function bound to the handler of one event
+type eventBinding struct {
+	function string
+	handler  EventHandler
+}
+
+// On binds the named DOM event - click, change, input, focus, blur, keydown,
+// submit and so on - to handler, replacing any previous handler for the event.
+// Handlers must be registered before the Window is started.
+func (ev *Events) On(event string, handler EventHandler) {
+	if ev.bound == nil {
+		ev.bound = map[string]eventBinding{}
+	}
+	b, ok := ev.bound[event]
+	if !ok {
+		b.function = functionName("event")
+	}
+	b.handler = handler
+	ev.bound[event] = b
+}
+
+// script is the JavaScript that hands event to Go, or empty if it is not bound
+func (ev *Events) script(event string) string {
+	b, ok := ev.bound[event]
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf(`%s(dali.event(event))`, b.function)
+}
+
+// attributes renders the on* attributes of the bound events, except those skipped
+func (ev *Events) attributes(skip ...string) string {
+	names := []string{}
+	for name := range ev.bound {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	attrs := ""
+next:
+	for _, name := range names {
+		for _, s := range skip {
+			if s == name {
+				continue next
+			}
+		}
+		attrs += attribute("on"+name, ev.script(name))
+	}
+	return attrs
+}
+
+// boundHandlers binds the JavaScript functions of the events to their handlers
+func (ev *Events) boundHandlers() map[string]interface{} {
+	handlers := map[string]interface{}{}
+	for _, b := range ev.bound {
+		handlers[b.function] = b.handler
+	}
+	return handlers
+}
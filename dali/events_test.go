@@ -0,0 +1,121 @@
+package dali
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// applied records the calls a sequencer makes
+type applied struct {
+	order []uint64
+}
+
+func (a *applied) call(s *sequencer, page, seq uint64) {
+	s.apply(sequenced{Page: page, Seq: seq}, func() { a.order = append(a.order, seq) })
+}
+
+func TestSequencerReorders(t *testing.T) {
+	s, a := &sequencer{}, &applied{}
+	for _, seq := range []uint64{1, 3, 2, 4} {
+		a.call(s, 1, seq)
+	}
+	expected := "[1 2 3 4]"
+	if got := fmt.Sprint(a.order); got != expected {
+		t.Errorf(`expected "%s" but got "%s"`, expected, got)
+	}
+}
+
+func TestSequencerAppliesPastMissing(t *testing.T) {
+	defer func(gap time.Duration) { sequenceGap = gap }(sequenceGap)
+	sequenceGap = 10 * time.Millisecond
+	s, a := &sequencer{}, &applied{}
+	a.call(s, 1, 3)
+	a.call(s, 1, 2)
+	s.lock.Lock()
+	if len(a.order) != 0 {
+		t.Errorf(`expected nothing to be applied before 1 but got "%v"`, a.order)
+	}
+	s.lock.Unlock()
+	time.Sleep(5 * sequenceGap)
+	a.call(s, 1, 1)
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	expected := "[2 3 1]"
+	if got := fmt.Sprint(a.order); got != expected {
+		t.Errorf(`expected "%s" but got "%s"`, expected, got)
+	}
+}
+
+func TestSequencerReload(t *testing.T) {
+	s, a := &sequencer{}, &applied{}
+	a.call(s, 1, 1)
+	a.call(s, 1, 3)
+	a.call(s, 2, 1)
+	a.call(s, 1, 2)
+	a.call(s, 2, 2)
+	expected := "[1 3 1 2 2]"
+	if got := fmt.Sprint(a.order); got != expected {
+		t.Errorf(`expected "%s" but got "%s"`, expected, got)
+	}
+	if s.timer != nil {
+		t.Errorf("expected nothing to be waited for")
+	}
+}
+
+func TestCanvasPointerEventsInOrder(t *testing.T) {
+	c := NewCanvas(10, 10, "c")
+	got := []float64{}
+	c.OnPointer(func(e PointerEvent) { got = append(got, e.X) })
+	handler := c.boundHandlers()[c.pointer].(func(pointerMessage))
+	for _, seq := range []uint64{2, 3, 1} {
+		handler(pointerMessage{PointerEvent{Type: "pointermove", X: float64(seq)}, sequenced{Page: 1, Seq: seq}})
+	}
+	expected := "[1 2 3]"
+	if s := fmt.Sprint(got); s != expected {
+		t.Errorf(`expected "%s" but got "%s"`, expected, s)
+	}
+	html := c.String()
+	if !strings.Contains(html, fmt.Sprintf(`onpointermove="dali.send(&#34;%s&#34;,dali.pointer(event,this))"`, c.pointer)) {
+		t.Errorf(`expected pointer events to be sent in order but got "%s"`, html)
+	}
+}
+
+func TestOnBindsEventsToHandlers(t *testing.T) {
+	b := &Button{ID: "save", ButtonText: "Save"}
+	b.On("keydown", func(e Event) (interface{}, error) { return "first", nil })
+	b.On("click", func(e Event) (interface{}, error) { return e.Target, nil })
+	function := b.bound["keydown"].function
+	b.On("keydown", func(e Event) (interface{}, error) { return e.Key, nil })
+
+	expected := fmt.Sprintf(` onclick="%s(dali.event(event))" onkeydown="%s(dali.event(event))"`, b.bound["click"].function, function)
+	if attrs := b.Events.attributes(); attrs != expected {
+		t.Errorf(`expected "%s" but got "%s"`, expected, attrs)
+	}
+	if attrs := b.Events.attributes("click"); strings.Contains(attrs, "onclick") {
+		t.Errorf(`expected click to be skipped but got "%s"`, attrs)
+	}
+
+	w := startedWindow(t, &fakeUI{}, b)
+	handler, ok := w.handlers[function].(EventHandler)
+	if !ok {
+		t.Fatalf("expected the keydown handler to be bound to %s", function)
+	}
+	if v, err := handler(Event{Type: "keydown", Key: "Enter"}); err != nil || v != "Enter" {
+		t.Errorf(`expected "%s" from the handler registered last but got "%v"`, "Enter", v)
+	}
+}
+
+func TestEventsDecodeFromThePage(t *testing.T) {
+	var m eventMessage
+	page := `{"type":"keydown","target":"name","value":"ab","key":"b","x":3.5,"shift":true,"page":7,"seq":2}`
+	if err := json.Unmarshal([]byte(page), &m); err != nil {
+		t.Fatal(err)
+	}
+	expected := eventMessage{Event{Type: "keydown", Target: "name", Value: "ab", Key: "b", X: 3.5, Shift: true}, sequenced{Page: 7, Seq: 2}}
+	if m != expected {
+		t.Errorf(`expected %+v but got %+v`, expected, m)
+	}
+}
@@ -0,0 +1,434 @@
+package dali
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FormValues are the values of a submitted form, by field name, in the order
+// the fields appear.  Unchecked checkboxes and radios are left out.
+type FormValues map[string][]string
+
+// Get returns the first value of the named field, or an empty string
+func (v FormValues) Get(name string) string {
+	if values := v[name]; len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// Decode copies the values into the fields of the struct pointed to by target.
+// A field is filled from the form field named by its `form` tag, or by its own
+// name when it has none; a tag of "-" leaves it alone.  Strings, bools, numbers
+// and slices of them are supported.  Values that do not convert are returned
+// as ValidationErrors.
+func (v FormValues) Decode(target interface{}) error {
+	r := reflect.ValueOf(target)
+	if r.Kind() != reflect.Ptr || r.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("Decode needs a pointer to a struct, not %T", target)
+	}
+	r = r.Elem()
+	errs := ValidationErrors{}
+	for i := 0; i < r.NumField(); i++ {
+		sf := r.Type().Field(i)
+		name := formField(sf)
+		if name == "" {
+			continue
+		}
+		values, ok := v[name]
+		if !ok {
+			if sf.Type.Kind() == reflect.Bool {
+				r.Field(i).SetBool(false)
+			}
+			continue
+		}
+		if err := setField(r.Field(i), values); err != nil {
+			errs[name] = err.Error()
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// formField is the name of the form field filling sf, or empty if none does
+func formField(sf reflect.StructField) string {
+	if sf.PkgPath != "" {
+		return ""
+	}
+	tag := strings.Split(sf.Tag.Get("form"), ",")[0]
+	switch tag {
+	case "-":
+		return ""
+	case "":
+		return sf.Name
+	}
+	return tag
+}
+
+// setField converts values into f
+func setField(f reflect.Value, values []string) error {
+	if f.Kind() == reflect.Slice {
+		s := reflect.MakeSlice(f.Type(), len(values), len(values))
+		for i, value := range values {
+			if err := setValue(s.Index(i), value); err != nil {
+				return err
+			}
+		}
+		f.Set(s)
+		return nil
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	return setValue(f, values[0])
+}
+
+// setValue converts value into f
+func setValue(f reflect.Value, value string) error {
+	switch f.Kind() {
+	case reflect.Ptr:
+		// an empty value leaves a pointer nil
+		if strings.TrimSpace(value) == "" {
+			f.Set(reflect.Zero(f.Type()))
+			return nil
+		}
+		v := reflect.New(f.Type().Elem())
+		if err := setValue(v.Elem(), value); err != nil {
+			return err
+		}
+		f.Set(v)
+	case reflect.String:
+		f.SetString(value)
+	case reflect.Bool:
+		// a checked box with no value attribute submits "on"
+		b, err := strconv.ParseBool(value)
+		if err != nil && value != "on" {
+			return fmt.Errorf("%q is not true or false", value)
+		}
+		f.SetBool(b || value == "on")
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(strings.TrimSpace(value), 10, f.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%q is not a whole number", value)
+		}
+		f.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(strings.TrimSpace(value), 10, f.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%q is not a positive whole number", value)
+		}
+		f.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(strings.TrimSpace(value), f.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%q is not a number", value)
+		}
+		f.SetFloat(n)
+	default:
+		return fmt.Errorf("cannot set a %s from a form", f.Type())
+	}
+	return nil
+}
+
+// ValidationErrors say what is wrong with the fields of a form, by field name.
+// When a submit handler returns them, each is shown next to its field.
+type ValidationErrors map[string]string
+
+// Error lists the errors by field name
+func (v ValidationErrors) Error() string {
+	names := []string{}
+	for name := range v {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	errs := []string{}
+	for _, name := range names {
+		errs = append(errs, fmt.Sprintf("%s: %s", name, v[name]))
+	}
+	return strings.Join(errs, "; ")
+}
+
+// FormHandler handles the values of a submitted form.  Returning
+// ValidationErrors shows them next to their fields; returning nil clears them.
+type FormHandler func(values FormValues) error
+
+// fieldElement is a form field that can show a validation error
+type fieldElement interface {
+	field() string
+	showInvalid(message string) error
+}
+
+// validity is embedded by form fields to show a validation error after them
+type validity struct {
+	message string
+}
+
+// show sets the error shown after the field with the given id, clearing it
+// when message is empty.  Only the error and the aria-invalid attribute of the
+// field change in a running page, so nothing the user is typing is touched.
+func (v *validity) show(l *live, id, message string) error {
+	if v.message == message {
+		return nil
+	}
+	v.message = message
+	errorID := id + "-error"
+	html := v.errorHTML(id)
+	return l.change(func(t *tree, n *vnode) {
+		if old := t.ids[errorID]; old != nil {
+			t.replace(old)
+		}
+		if html == "" {
+			n.removeAttr("aria-invalid")
+			return
+		}
+		n.setAttr("aria-invalid", "true")
+		for at, c := range n.parent.children {
+			if c == n {
+				t.splice(n.parent, at+1, 0, parseHTML(html)...)
+				break
+			}
+		}
+	}, `var e=document.getElementById(%s);if(e){e.remove();}var h=%s;`+
+		`if(h){el.setAttribute("aria-invalid","true");el.insertAdjacentHTML("afterend",h);}else{el.removeAttribute("aria-invalid");}`,
+		errorID, html)
+}
+
+// Invalid is the validation error shown after the field, if any
+func (v *validity) Invalid() string { return v.message }
+
+// invalidAttribute marks an invalid field for assistive technology
+func (v *validity) invalidAttribute() string {
+	if v.message == "" {
+		return ""
+	}
+	return ` aria-invalid="true"`
+}
+
+// errorHTML renders the error after the field with the given id
+func (v *validity) errorHTML(id string) string {
+	if v.message == "" {
+		return ""
+	}
+	return fmt.Sprintf(`<span class="dali-error"%s>%s</span>`, attribute("id", id+"-error"), escape(v.message))
+}
+
+// Form groups fields whose values are delivered to Go when it is submitted.
+// The page is never reloaded: submitting calls the handlers given to OnSubmit.
+type Form struct {
+	ID         string
+	StyleName  string
+	Elements   *Elements
+	NoValidate bool // skip the browser's own checks, such as required fields
+	submit     string
+	onSubmit   []FormHandler
+	unmatched  ValidationErrors
+	BaseElement
+}
+
+// NewForm creates an empty Form
+func NewForm(name string) *Form {
+	return &Form{
+		ID:       name,
+		Elements: &Elements{slice: []*Element{}},
+		submit:   functionName("form"),
+	}
+}
+
+// String for Form
+func (f *Form) String() string {
+	attrs := attribute("id", f.ID)
+	if f.StyleName != "" {
+		attrs += attribute("style", f.StyleName)
+	}
+	if f.NoValidate {
+		attrs += ` novalidate`
+	}
+	onsubmit := fmt.Sprintf("%s(dali.form(event))", f.function())
+	if handler := f.Events.script("submit"); handler != "" {
+		onsubmit = fmt.Sprintf("%s;%s", onsubmit, handler)
+	}
+	attrs += attribute("onsubmit", onsubmit)
+
+	return fmt.Sprintf(`<form%s%s%s>%s%s</form>`, attrs, f.attributes(), f.Events.attributes("submit"), f.Elements, f.unmatchedHTML())
+}
+
+// unmatchedHTML renders the errors for fields that are not in the Form, shown at its end
+func (f *Form) unmatchedHTML() string {
+	errs := ""
+	names := []string{}
+	for name := range f.unmatched {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		errs += fmt.Sprintf(`<div class="dali-error">%s</div>`, escape(f.unmatched[name]))
+	}
+	return errs
+}
+
+// function is the name of the JavaScript function delivering the form to Go
+func (f *Form) function() string {
+	if f.submit == "" {
+		f.submit = functionName("form")
+	}
+	return f.submit
+}
+
+// Name of the Form
+func (f *Form) Name() string { return f.ID }
+
+// Style of the Form
+func (f *Form) Style() string { return f.StyleName }
+
+// Styles of the Form
+func (f *Form) Styles() Styles { return parseStyles(f.StyleName) }
+
+// Children returns the fields and other Elements of the Form
+func (f *Form) Children() *Elements { return f.Elements }
+
+// OnSubmit calls handler with the values of the Form whenever it is submitted
+func (f *Form) OnSubmit(handler FormHandler) {
+	f.onSubmit = append(f.onSubmit, handler)
+}
+
+// OnSubmitInto decodes the values of the Form into target, a pointer to a
+// struct, and then calls handler, whenever the Form is submitted.  Values
+// that do not convert are shown as validation errors without calling handler.
+func (f *Form) OnSubmitInto(target interface{}, handler func() error) {
+	f.OnSubmit(func(values FormValues) error {
+		if err := values.Decode(target); err != nil {
+			return err
+		}
+		return handler()
+	})
+}
+
+// SetErrors shows each error next to the field it names, clearing any others.
+// Errors for fields that are not in the Form are shown at its end.  Only the
+// errors change in a running page: the values of the fields are left alone.
+func (f *Form) SetErrors(errs ValidationErrors) error {
+	unmatched := ValidationErrors{}
+	for name, message := range errs {
+		unmatched[name] = message
+	}
+	var err error
+	var walk func(els *Elements)
+	walk = func(els *Elements) {
+		if els == nil {
+			return
+		}
+		for _, el := range els.slice {
+			if field, ok := (*el).(fieldElement); ok {
+				if e := field.showInvalid(errs[field.field()]); e != nil && err == nil {
+					err = e
+				}
+				delete(unmatched, field.field())
+			}
+			walk((*el).Children())
+		}
+	}
+	walk(f.Elements)
+	f.unmatched = unmatched
+	if err != nil {
+		return err
+	}
+	html := f.unmatchedHTML()
+	return f.live.change(func(t *tree, n *vnode) {
+		for at := 0; at < len(n.children); at++ {
+			if c := n.children[at]; c.tag == "div" && c.attrs["class"] == "dali-error" {
+				t.splice(n, at, 1)
+				at--
+			}
+		}
+		t.splice(n, len(n.children), 0, parseHTML(html)...)
+	}, `Array.prototype.forEach.call(el.querySelectorAll(":scope > .dali-error"),function(e){e.remove();});`+
+		`el.insertAdjacentHTML("beforeend",%s);`, html)
+}
+
+// ClearErrors removes every validation error from the Form
+func (f *Form) ClearErrors() error { return f.SetErrors(nil) }
+
+// SetStyle sets a CSS property of the Form, or removes it if value is empty
+func (f *Form) SetStyle(property, value string) error {
+	return f.live.setStyle(&f.StyleName, property, value)
+}
+
+// Show displays the Form
+func (f *Form) Show() error { return f.live.show(&f.StyleName) }
+
+// Hide hides the Form
+func (f *Form) Hide() error { return f.live.hide(&f.StyleName) }
+
+// boundHandlers binds the function delivering the Form, along with those registered with On
+func (f *Form) boundHandlers() map[string]interface{} {
+	handlers := f.Events.boundHandlers()
+	handlers[f.function()] = f.submitted
+	return handlers
+}
+
+// submitted runs the handlers on the values from the page, showing any validation errors
+func (f *Form) submitted(values map[string][]string) error {
+	errs := ValidationErrors{}
+	for _, handler := range f.onSubmit {
+		err := handler(FormValues(values))
+		if v, ok := err.(ValidationErrors); ok {
+			for name, message := range v {
+				errs[name] = message
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return f.SetErrors(errs)
+}
+
+// Label is the caption of a form field, whose ID it names in For
+type Label struct {
+	ID        string
+	For       string
+	LabelText string
+	StyleName string
+	BaseElement
+}
+
+// NewLabel creates a Label captioning the field with the given ID
+func NewLabel(name, field, text string) *Label {
+	return &Label{ID: name, For: field, LabelText: text}
+}
+
+// String for Label
+func (l *Label) String() string {
+	attrs := ""
+	if l.ID != "" {
+		attrs += attribute("id", l.ID)
+	}
+	if l.For != "" {
+		attrs += attribute("for", l.For)
+	}
+	if l.StyleName != "" {
+		attrs += attribute("style", l.StyleName)
+	}
+	return fmt.Sprintf(`<label%s%s%s>%s</label>`, attrs, l.attributes(), l.Events.attributes(), escape(l.LabelText))
+}
+
+// Name of the Label
+func (l *Label) Name() string { return l.ID }
+
+// Style of the Label
+func (l *Label) Style() string { return l.StyleName }
+
+// Styles of the Label
+func (l *Label) Styles() Styles { return parseStyles(l.StyleName) }
+
+// SetText changes the caption of the Label
+func (l *Label) SetText(text string) error {
+	l.LabelText = text
+	return l.live.setText(text)
+}
@@ -0,0 +1,42 @@
+package dali
+
+import (
+	"reflect"
+	"testing"
+)
+
+type signup struct {
+	Name     string
+	Age      int `form:"age"`
+	Ratio    float64
+	Agree    bool
+	Tags     []string
+	Internal string `form:"-"`
+}
+
+func TestDecodeFillsFields(t *testing.T) {
+	s := signup{Agree: true, Internal: "kept"}
+	values := FormValues{"Name": {"Ann"}, "age": {" 42 "}, "Ratio": {"0.5"}, "Tags": {"a", "b"}, "Internal": {"lost"}}
+	if err := values.Decode(&s); err != nil {
+		t.Fatal(err)
+	}
+	expected := signup{Name: "Ann", Age: 42, Ratio: 0.5, Tags: []string{"a", "b"}, Internal: "kept"}
+	if !reflect.DeepEqual(s, expected) {
+		t.Errorf(`expected %+v but got %+v`, expected, s)
+	}
+}
+
+func TestDecodeReportsBadValues(t *testing.T) {
+	s := signup{}
+	err := FormValues{"age": {"old"}, "Agree": {"on"}}.Decode(&s)
+	errs, ok := err.(ValidationErrors)
+	if !ok || len(errs) != 1 || errs["age"] == "" {
+		t.Errorf(`expected an error for age but got "%v"`, err)
+	}
+	if !s.Agree {
+		t.Errorf("expected a checked box to be true")
+	}
+	if err := (FormValues{}).Decode(s); err == nil {
+		t.Errorf("expected an error for a struct that is not a pointer")
+	}
+}
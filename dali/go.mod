@@ -0,0 +1,5 @@
+module github.com/matthewapeters/dali
+
+go 1.14
+
+require github.com/zserge/lorca v0.1.9
@@ -0,0 +1,99 @@
+package dali
+
+import "fmt"
+
+//HeadElement provides the Head
+type HeadElement struct {
+	Title    string
+	Elements *Elements
+	BaseElement
+}
+
+//Children returns the Elements
+func (h *HeadElement) Children() *Elements { return h.Elements }
+
+//String for Head
+func (h *HeadElement) String() string { return h.render("") }
+
+// render renders the Head with extra markup from the Window after its Elements
+func (h *HeadElement) render(extra string) string {
+	return fmt.Sprintf(`<head>%s%s</head>`, h.Elements, extra)
+}
+
+// NewHeadElement to create a new Head Element
+func NewHeadElement() *HeadElement {
+	els := Elements{slice: []*Element{}}
+
+	return &HeadElement{
+		Elements: &els,
+	}
+}
+
+// ScriptElement is for scripts
+type ScriptElement struct {
+	URL  string
+	Text string
+	ID   string
+	BaseElement
+}
+
+func (scr *ScriptElement) String() string {
+	src := ""
+	if scr.URL != "" {
+		src = attribute("src", scr.URL)
+	}
+	name := ""
+	if scr.ID != "" {
+		name = attribute("id", scr.Name())
+	}
+	return fmt.Sprintf(`<script%s%s%s>%s</script>`, src, name, scr.attributes(), scr.Text)
+}
+
+// Name of script
+func (scr *ScriptElement) Name() string { return scr.ID }
+
+//TitleElement for createing window titles
+type TitleElement struct {
+	Text string
+	BaseElement
+}
+
+//String stringer for Title
+func (t *TitleElement) String() string {
+	return fmt.Sprintf(`<title>%s</title>`, escape(t.Text))
+}
+
+//BodyElement for holding the body of the page
+type BodyElement struct {
+	Elements *Elements
+	BaseElement
+	Binding *Binding
+}
+
+func (b *BodyElement) String() string {
+	onLoad := ""
+	if b.Binding != nil && jsCall(b.Binding.FunctionName) != "" {
+		onLoad = attribute("onload", jsCall(b.Binding.FunctionName))
+	}
+	return fmt.Sprintf(`<body%s%s%s>%s</body>`, onLoad, b.attributes(), b.Events.attributes(), b.Elements)
+}
+
+//Children return the Elements
+func (b *BodyElement) Children() *Elements { return b.Elements }
+
+// Bindings returns the Binding
+func (b *BodyElement) Bindings() *Binding { return b.Binding }
+
+//NewBodyElement creates a body element
+func NewBodyElement(onLoad string) *BodyElement {
+	els := Elements{slice: []*Element{}}
+	var binding *Binding
+	if onLoad != "" {
+		binding = &Binding{FunctionName: onLoad}
+	}
+
+	return &BodyElement{
+		Elements: &els,
+		Binding:  binding,
+	}
+}
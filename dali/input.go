@@ -0,0 +1,298 @@
+package dali
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+)
+
+// InputType is the kind of an Input
+type InputType string
+
+const (
+	//TextInput is a single line of text
+	TextInput = InputType("text")
+	//NumberInput is a number
+	NumberInput = InputType("number")
+	//PasswordInput is text that is not shown
+	PasswordInput = InputType("password")
+	//EmailInput is an email address
+	EmailInput = InputType("email")
+	//CheckboxInput is a check box
+	CheckboxInput = InputType("checkbox")
+	//RadioInput is one choice among the radio inputs sharing its name
+	RadioInput = InputType("radio")
+	//RangeInput is a slider
+	RangeInput = InputType("range")
+	//ColorInput is a color picker
+	ColorInput = InputType("color")
+	//DateInput is a date picker
+	DateInput = InputType("date")
+)
+
+// Input is a form input.  Its value is kept in step with the page as the user
+// edits it, so it can be read from Go at any time.
+type Input struct {
+	ID          string
+	Field       string // the name the value is submitted under
+	Type        InputType
+	Placeholder string
+	Min         string
+	Max         string
+	Step        string
+	List        string // the ID of a DataList suggesting values
+	StyleName   string
+	Required    bool
+	Disabled    bool
+	value       string
+	checked     bool
+	sync        string
+	onInput     []func(value string)
+	onChange    []func(value string)
+	order       sequencer
+	lock        sync.Mutex
+	validity
+	BaseElement
+}
+
+// NewInput creates an Input of the given type, with name as both its ID and its Field
+func NewInput(inputType InputType, name, value string) *Input {
+	return &Input{
+		ID:    name,
+		Field: name,
+		Type:  inputType,
+		value: value,
+		sync:  functionName("input"),
+	}
+}
+
+// String for Input
+func (i *Input) String() string {
+	i.lock.Lock()
+	value, checked := i.value, i.checked
+	i.lock.Unlock()
+
+	attrs := attribute("type", string(i.Type)) + attribute("id", i.ID)
+	if i.Field != "" {
+		attrs += attribute("name", i.Field)
+	}
+	if i.Type == CheckboxInput || i.Type == RadioInput {
+		if value != "" {
+			attrs += attribute("value", value)
+		}
+		if checked {
+			attrs += ` checked`
+		}
+	} else {
+		attrs += attribute("value", value)
+	}
+	for _, a := range [][2]string{{"placeholder", i.Placeholder}, {"min", i.Min}, {"max", i.Max}, {"step", i.Step}, {"list", i.List}, {"style", i.StyleName}} {
+		if a[1] != "" {
+			attrs += attribute(a[0], a[1])
+		}
+	}
+	if i.Required {
+		attrs += ` required`
+	}
+	if i.Disabled {
+		attrs += ` disabled`
+	}
+	for _, e := range []string{"input", "change"} {
+		attrs += attribute("on"+e, i.script(e))
+	}
+	return fmt.Sprintf(`<input%s%s%s%s>%s`, attrs, i.invalidAttribute(), i.attributes(), i.Events.attributes("input", "change"), i.errorHTML(i.ID))
+}
+
+// script keeps the Go value in step, then runs any handler registered with On
+func (i *Input) script(event string) string {
+	if i.sync == "" {
+		i.sync = functionName("input")
+	}
+	script := fmt.Sprintf("dali.send(%s,dali.event(event))", jsString(i.sync))
+	if handler := i.Events.script(event); handler != "" {
+		script = fmt.Sprintf("%s;%s", script, handler)
+	}
+	return script
+}
+
+// Name of the Input
+func (i *Input) Name() string { return i.ID }
+
+// field is the name the Input is submitted under
+func (i *Input) field() string { return i.Field }
+
+// Style of the Input
+func (i *Input) Style() string { return i.StyleName }
+
+// Styles of the Input
+func (i *Input) Styles() Styles { return parseStyles(i.StyleName) }
+
+// Value is the current value of the Input
+func (i *Input) Value() string {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	return i.value
+}
+
+// Number is the current value of the Input as a number
+func (i *Input) Number() (float64, error) {
+	return strconv.ParseFloat(i.Value(), 64)
+}
+
+// Checked is true when a checkbox or radio Input is checked
+func (i *Input) Checked() bool {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	return i.checked
+}
+
+// SetValue changes the value of the Input
+func (i *Input) SetValue(value string) error {
+	i.lock.Lock()
+	i.value = value
+	i.lock.Unlock()
+	mirror := func(t *tree, n *vnode) { n.setAttr("value", value) }
+	if i.Type == CheckboxInput || i.Type == RadioInput {
+		mirror = mirrorAttr("value", value)
+	}
+	return i.live.change(mirror, `el.value=%s;`, value)
+}
+
+// SetChecked checks or unchecks a checkbox or radio Input
+func (i *Input) SetChecked(checked bool) error {
+	i.lock.Lock()
+	i.checked = checked
+	i.lock.Unlock()
+	if i.Type == RadioInput && checked {
+		i.uncheckSiblings()
+	}
+	return i.live.change(func(t *tree, n *vnode) {
+		if checked {
+			n.setAttr("checked", "")
+		} else {
+			n.removeAttr("checked")
+		}
+	}, `el.checked=%s;`, checked)
+}
+
+// SetStyle sets a CSS property of the Input, or removes it if value is empty
+func (i *Input) SetStyle(property, value string) error {
+	return i.live.setStyle(&i.StyleName, property, value)
+}
+
+// Show displays the Input
+func (i *Input) Show() error { return i.live.show(&i.StyleName) }
+
+// Hide hides the Input
+func (i *Input) Hide() error { return i.live.hide(&i.StyleName) }
+
+// OnInput calls f with the value every time the user edits it
+func (i *Input) OnInput(f func(value string)) {
+	i.onInput = append(i.onInput, f)
+}
+
+// OnChange calls f with the value when the user commits a change to it.  For
+// checkboxes and radios the value is "true" or "false" for checked.
+func (i *Input) OnChange(f func(value string)) {
+	i.onChange = append(i.onChange, f)
+}
+
+// boundHandlers binds the function keeping the value in step, along with those registered with On
+func (i *Input) boundHandlers() map[string]interface{} {
+	handlers := i.Events.boundHandlers()
+	if i.sync == "" {
+		i.sync = functionName("input")
+	}
+	handlers[i.sync] = i.update
+	return handlers
+}
+
+// update takes the value from an input or change event in the page, one
+// event at a time and in the order they happened: a change event that reaches
+// Go ahead of the input events before it waits for them
+func (i *Input) update(e eventMessage) {
+	i.order.apply(e.sequenced, func() { i.apply(e.Event) })
+}
+
+// apply takes the value from an event, then calls the callbacks for it
+func (i *Input) apply(e Event) {
+	i.lock.Lock()
+	if i.Type == CheckboxInput || i.Type == RadioInput {
+		i.checked = e.Checked
+	} else {
+		i.value = e.Value
+	}
+	i.lock.Unlock()
+	if i.Type == RadioInput && e.Checked {
+		i.uncheckSiblings()
+	}
+	i.live.mirror(func(t *tree, n *vnode) {
+		switch {
+		case i.Type != CheckboxInput && i.Type != RadioInput:
+			n.setAttr("value", e.Value)
+		case e.Checked:
+			n.setAttr("checked", "")
+		default:
+			n.removeAttr("checked")
+		}
+	})
+
+	value := e.Value
+	if i.Type == CheckboxInput || i.Type == RadioInput {
+		value = strconv.FormatBool(e.Checked)
+	}
+	callbacks := i.onInput
+	if e.Type == "change" {
+		callbacks = i.onChange
+	}
+	for _, f := range callbacks {
+		f(value)
+	}
+}
+
+// uncheckSiblings unchecks the other radio Inputs of the group of a radio the
+// page has just checked: those of the same Window and form submitted under
+// the same Field.  The page unchecks them itself, without telling Go.
+func (i *Input) uncheckSiblings() {
+	if i.live.window == nil || i.Field == "" {
+		return
+	}
+	type radio struct {
+		input *Input
+		form  Element
+	}
+	radios := []radio{}
+	var form Element
+	var walk func(els *Elements, form Element)
+	walk = func(els *Elements, in Element) {
+		if els == nil {
+			return
+		}
+		for _, el := range els.slice {
+			if r, ok := (*el).(*Input); ok && r.Type == RadioInput && r.Field == i.Field {
+				radios = append(radios, radio{r, in})
+				if r == i {
+					form = in
+				}
+			}
+			if f, ok := (*el).(*Form); ok {
+				walk(f.Elements, f)
+			} else {
+				walk((*el).Children(), in)
+			}
+		}
+	}
+	walk(i.live.window.Elements, nil)
+	for _, r := range radios {
+		if r.input == i || r.form != form {
+			continue
+		}
+		r.input.lock.Lock()
+		r.input.checked = false
+		r.input.lock.Unlock()
+		r.input.live.mirror(func(t *tree, n *vnode) { n.removeAttr("checked") })
+	}
+}
+
+// showInvalid shows a validation error after the Input
+func (i *Input) showInvalid(message string) error { return i.validity.show(&i.live, i.ID, message) }
@@ -0,0 +1,74 @@
+package dali
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestInputAppliesEventsInOrder(t *testing.T) {
+	i := NewInput(TextInput, "name", "")
+	changed := []string{}
+	i.OnChange(func(value string) { changed = append(changed, value) })
+	update := i.boundHandlers()[i.sync].(func(eventMessage))
+	// the change event overtakes the input events before it on the way to Go
+	update(eventMessage{Event{Type: "change", Value: "abc"}, sequenced{Page: 1, Seq: 3}})
+	update(eventMessage{Event{Type: "input", Value: "ab"}, sequenced{Page: 1, Seq: 2}})
+	update(eventMessage{Event{Type: "input", Value: "a"}, sequenced{Page: 1, Seq: 1}})
+	if value := i.Value(); value != "abc" {
+		t.Errorf(`expected "%s" but got "%s"`, "abc", value)
+	}
+	if s := fmt.Sprint(changed); s != "[abc]" {
+		t.Errorf(`expected "%s" but got "%s"`, "[abc]", s)
+	}
+}
+
+func TestRadiosOfAGroupUncheckEachOther(t *testing.T) {
+	radio := func(id, field string, checked bool) *Input {
+		r := NewInput(RadioInput, id, id)
+		r.Field, r.checked = field, checked
+		return r
+	}
+	small, large, other := radio("small", "size", true), radio("large", "size", false), radio("red", "color", true)
+	w := startedWindow(t, &fakeUI{}, small, large, other)
+
+	changed := []string{}
+	large.OnChange(func(value string) { changed = append(changed, value) })
+	large.update(eventMessage{Event: Event{Type: "change", Checked: true}})
+	if small.Checked() || !large.Checked() || !other.Checked() {
+		t.Errorf("expected only large of the size group to be checked but got %v %v, and %v for red", small.Checked(), large.Checked(), other.Checked())
+	}
+	if fmt.Sprint(changed) != "[true]" {
+		t.Errorf(`expected "%s" but got "%v"`, "[true]", changed)
+	}
+	inStep(t, w, "checking a radio in the page")
+
+	if err := small.SetChecked(true); err != nil {
+		t.Fatal(err)
+	}
+	if !small.Checked() || large.Checked() {
+		t.Errorf("expected checking small from Go to uncheck large")
+	}
+	inStep(t, w, "checking a radio from Go")
+}
+
+func TestInputSetFromGo(t *testing.T) {
+	name := NewInput(TextInput, "name", "Ann")
+	name.Placeholder = "Your name"
+	expected := `<input type="text" id="name" name="name" value="Ann" placeholder="Your name"`
+	if html := name.String(); !strings.HasPrefix(html, expected) {
+		t.Errorf(`expected "%s" but got "%s"`, expected, html)
+	}
+	ui := &fakeUI{}
+	w := startedWindow(t, ui, name)
+	if err := name.SetValue(`"Bo"`); err != nil {
+		t.Fatal(err)
+	}
+	if name.Value() != `"Bo"` || !strings.Contains(ui.evals[len(ui.evals)-1], `el.value="\"Bo\"";`) {
+		t.Errorf(`expected the value to be set in the page but got "%s"`, ui.evals[len(ui.evals)-1])
+	}
+	inStep(t, w, "setting the value")
+	if _, err := name.Number(); err == nil {
+		t.Errorf("expected a name not to be a number")
+	}
+}
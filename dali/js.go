@@ -0,0 +1,88 @@
+package dali
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync/atomic"
+
+	"github.com/zserge/lorca"
+)
+
+// jsFunction matches a reference to a JavaScript function, such as draw or dali.patch
+var jsFunction = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*(\.[A-Za-z_$][A-Za-z0-9_$]*)*$`)
+
+// functionCount numbers the JavaScript functions dali generates for bindings
+var functionCount uint64
+
+// functionName returns a unique JavaScript function name starting with prefix
+func functionName(prefix string) string {
+	return fmt.Sprintf("dali_%s_%d", prefix, atomic.AddUint64(&functionCount, 1))
+}
+
+// jsArgs renders Go values as a comma separated list of JavaScript literals.
+// Values are JSON encoded, so strings can never break out of their quotes.
+func jsArgs(args ...interface{}) (string, error) {
+	literals := make([]string, len(args))
+	for i, arg := range args {
+		b, err := json.Marshal(arg)
+		if err != nil {
+			return "", err
+		}
+		literals[i] = string(b)
+	}
+	return strings.Join(literals, ","), nil
+}
+
+// jsString is s as a JavaScript string literal
+func jsString(s string) string {
+	literal, _ := jsArgs(s)
+	return literal
+}
+
+// jsCall is JavaScript calling the named function without arguments, or empty
+// if name is not a function reference: names are never trusted as script
+func jsCall(name string) string {
+	if !jsFunction.MatchString(name) {
+		return ""
+	}
+	return fmt.Sprintf("%s()", name)
+}
+
+// Call calls the JavaScript function fn in the page shown by ui and returns its
+// result.  fn must name a function, like alert or dali.patch; the arguments are
+// passed as JSON, so no Go string is ever run as script.
+func Call(ui lorca.UI, fn string, args ...interface{}) lorca.Value {
+	if !jsFunction.MatchString(fn) {
+		return errorValue{fmt.Errorf("%q is not a JavaScript function", fn)}
+	}
+	a, err := jsArgs(args...)
+	if err != nil {
+		return errorValue{err}
+	}
+	return ui.Eval(fmt.Sprintf("%s(%s)", fn, a))
+}
+
+// Call calls the JavaScript function fn in the page of the Window with args passed as JSON
+func (w *Window) Call(fn string, args ...interface{}) lorca.Value {
+	ui := w.started()
+	if ui == nil {
+		return errorValue{fmt.Errorf("Window has not been started")}
+	}
+	return Call(ui, fn, args...)
+}
+
+// errorValue is a lorca.Value for a call that could not be made
+type errorValue struct {
+	err error
+}
+
+func (v errorValue) Err() error                     { return v.err }
+func (v errorValue) To(interface{}) error           { return v.err }
+func (v errorValue) Float() float32                 { return 0 }
+func (v errorValue) Int() int                       { return 0 }
+func (v errorValue) String() string                 { return "" }
+func (v errorValue) Bool() bool                     { return false }
+func (v errorValue) Object() map[string]lorca.Value { return map[string]lorca.Value{} }
+func (v errorValue) Array() []lorca.Value           { return []lorca.Value{} }
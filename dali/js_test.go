@@ -0,0 +1,40 @@
+package dali
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCallPassesArgumentsAsJSON(t *testing.T) {
+	ui := &fakeUI{}
+	w := startedWindow(t, ui)
+	evals := len(ui.evals)
+	if err := w.Call("dali.setText", `"</script><script>alert(1)//`, 3, []string{"a"}).Err(); err != nil {
+		t.Fatal(err)
+	}
+	expected := `dali.setText("\"\u003c/script\u003e\u003cscript\u003ealert(1)//",3,["a"])`
+	if called := ui.evals[evals:]; len(called) != 1 || called[0] != expected {
+		t.Errorf(`expected "%s" but got %v`, expected, called)
+	}
+
+	for _, fn := range []string{"alert(1);f", "f()", "a.b c", "", "1f"} {
+		if err := w.Call(fn).Err(); err == nil {
+			t.Errorf(`expected "%s" to be refused as a function`, fn)
+		}
+	}
+	if len(ui.evals) != evals+1 {
+		t.Errorf("expected refused calls not to reach the page but got %v", ui.evals[evals+1:])
+	}
+	if err := NewWindow(10, 10, "", "").Call("alert").Err(); err == nil || !strings.Contains(err.Error(), "not been started") {
+		t.Errorf(`expected an error calling a Window that has not started but got %v`, err)
+	}
+}
+
+func TestJSCallRefusesScript(t *testing.T) {
+	if js := jsCall("dali.patch"); js != "dali.patch()" {
+		t.Errorf(`expected "%s" but got "%s"`, "dali.patch()", js)
+	}
+	if js := jsCall("x);alert(1"); js != "" {
+		t.Errorf(`expected nothing but got "%s"`, js)
+	}
+}
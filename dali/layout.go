@@ -0,0 +1,345 @@
+package dali
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Align places children along an axis of a layout container
+type Align string
+
+const (
+	// AlignStart packs children at the start
+	AlignStart = Align("start")
+	// AlignCenter centers children
+	AlignCenter = Align("center")
+	// AlignEnd packs children at the end
+	AlignEnd = Align("end")
+	// AlignStretch stretches children to fill the container
+	AlignStretch = Align("stretch")
+	// AlignBaseline lines children up by the baselines of their text
+	AlignBaseline = Align("baseline")
+	// AlignSpaceBetween spreads children out with the space between them
+	AlignSpaceBetween = Align("space-between")
+	// AlignSpaceAround spreads children out with half as much space at the ends
+	AlignSpaceAround = Align("space-around")
+	// AlignSpaceEvenly spreads children out with the same space everywhere
+	AlignSpaceEvenly = Align("space-evenly")
+)
+
+// Breakpoint changes a layout container once the window is at least MinWidth
+// pixels wide.  Only the fields that are set change.
+type Breakpoint struct {
+	MinWidth  int
+	Gap       string
+	Columns   int    // for a Grid, the number of columns
+	Direction string // for a Row or Column, row or column
+	Hidden    bool
+}
+
+// styled is an element with rules that the Window puts in the document head
+type styled interface {
+	rules(css *CSS)
+}
+
+// layout holds what every layout container has
+type layout struct {
+	scope string
+}
+
+// newLayout gives a layout container a scoped class made from name
+func newLayout(el *BaseElement, name string) layout {
+	scope := ScopedClass(name)
+	el.classes = append([]string{scope}, el.classes...)
+	return layout{scope: scope}
+}
+
+// selector selects the container in its rules: by its scoped class, or by its
+// ID when it was made without its constructor
+func (l *layout) selector(id string) string {
+	if l.scope != "" {
+		return "." + l.scope
+	}
+	if id == "" {
+		return ""
+	}
+	return `[id="` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(id) + `"]`
+}
+
+// breakpoints adds the media rules of the breakpoints to css
+func breakpoints(css *CSS, selector string, points []Breakpoint) {
+	for _, b := range points {
+		r := css.Media(fmt.Sprintf("(min-width: %dpx)", b.MinWidth), selector).Set("gap", b.Gap)
+		if b.Columns > 0 {
+			r.Set("grid-template-columns", fmt.Sprintf("repeat(%d,minmax(0,1fr))", b.Columns))
+		}
+		r.Set("flex-direction", b.Direction)
+		if b.Hidden {
+			r.Set("display", "none")
+		}
+	}
+}
+
+// renderContainer renders a layout container as a div holding its Elements
+func renderContainer(id, style string, el *BaseElement, els *Elements) string {
+	attrs := attribute("id", id)
+	if style != "" {
+		attrs += attribute("style", style)
+	}
+	return fmt.Sprintf(`<div%s%s%s>%s</div>`, attrs, el.attributes(), el.Events.attributes(), els)
+}
+
+// Row lays its Elements out side by side
+type Row struct {
+	ID          string
+	StyleName   string
+	Elements    *Elements
+	Gap         string // space between children, such as Px(8)
+	Align       Align  // places children across the row
+	Justify     Align  // places children along the row
+	Wrap        bool   // lets children wrap onto more lines
+	Breakpoints []Breakpoint
+	layout
+	BaseElement
+}
+
+// NewRow creates an empty Row
+func NewRow(name string) *Row {
+	r := &Row{ID: name, Elements: &Elements{slice: []*Element{}}}
+	r.layout = newLayout(&r.BaseElement, "dali-row")
+	return r
+}
+
+// String for Row
+func (r *Row) String() string {
+	return renderContainer(r.ID, r.StyleName, &r.BaseElement, r.Elements)
+}
+
+// rules adds the rules laying out the Row to css
+func (r *Row) rules(css *CSS) {
+	selector := r.selector(r.ID)
+	if selector == "" {
+		return
+	}
+	flexRules(css, selector, "row", r.Gap, r.Align, r.Justify, r.Wrap)
+	breakpoints(css, selector, r.Breakpoints)
+}
+
+// flexRules adds the rule for a flex container to css
+func flexRules(css *CSS, selector, direction, gap string, align, justify Align, wrap bool) {
+	r := css.Rule(selector).Display("flex").Set("flex-direction", direction).Set("gap", gap).
+		Set("align-items", string(align)).Set("justify-content", string(justify))
+	if wrap {
+		r.Set("flex-wrap", "wrap")
+	}
+}
+
+// Name of the Row
+func (r *Row) Name() string { return r.ID }
+
+// Style of the Row
+func (r *Row) Style() string { return r.StyleName }
+
+// Styles of the Row
+func (r *Row) Styles() Styles { return parseStyles(r.StyleName) }
+
+// Children returns the Elements of the Row
+func (r *Row) Children() *Elements { return r.Elements }
+
+// SetStyle sets a CSS property of the Row, or removes it if value is empty
+func (r *Row) SetStyle(property, value string) error {
+	return r.live.setStyle(&r.StyleName, property, value)
+}
+
+// Show displays the Row
+func (r *Row) Show() error { return r.live.show(&r.StyleName) }
+
+// Hide hides the Row
+func (r *Row) Hide() error { return r.live.hide(&r.StyleName) }
+
+// Column lays its Elements out one above the other
+type Column struct {
+	ID          string
+	StyleName   string
+	Elements    *Elements
+	Gap         string // space between children, such as Px(8)
+	Align       Align  // places children across the column
+	Justify     Align  // places children down the column
+	Breakpoints []Breakpoint
+	layout
+	BaseElement
+}
+
+// NewColumn creates an empty Column
+func NewColumn(name string) *Column {
+	c := &Column{ID: name, Elements: &Elements{slice: []*Element{}}}
+	c.layout = newLayout(&c.BaseElement, "dali-column")
+	return c
+}
+
+// String for Column
+func (c *Column) String() string {
+	return renderContainer(c.ID, c.StyleName, &c.BaseElement, c.Elements)
+}
+
+// rules adds the rules laying out the Column to css
+func (c *Column) rules(css *CSS) {
+	selector := c.selector(c.ID)
+	if selector == "" {
+		return
+	}
+	flexRules(css, selector, "column", c.Gap, c.Align, c.Justify, false)
+	breakpoints(css, selector, c.Breakpoints)
+}
+
+// Name of the Column
+func (c *Column) Name() string { return c.ID }
+
+// Style of the Column
+func (c *Column) Style() string { return c.StyleName }
+
+// Styles of the Column
+func (c *Column) Styles() Styles { return parseStyles(c.StyleName) }
+
+// Children returns the Elements of the Column
+func (c *Column) Children() *Elements { return c.Elements }
+
+// SetStyle sets a CSS property of the Column, or removes it if value is empty
+func (c *Column) SetStyle(property, value string) error {
+	return c.live.setStyle(&c.StyleName, property, value)
+}
+
+// Show displays the Column
+func (c *Column) Show() error { return c.live.show(&c.StyleName) }
+
+// Hide hides the Column
+func (c *Column) Hide() error { return c.live.hide(&c.StyleName) }
+
+// Grid lays its Elements out in rows of columns, filling each row in turn
+type Grid struct {
+	ID             string
+	StyleName      string
+	Elements       *Elements
+	Columns        int    // the number of equal columns
+	MinColumnWidth string // fits as many columns of at least this width as there is room for, instead of Columns
+	Template       string // a grid-template-columns value, instead of Columns or MinColumnWidth
+	Gap            string // space between cells, such as Px(8)
+	Align          Align  // places children within their cells vertically
+	Justify        Align  // places children within their cells horizontally
+	Breakpoints    []Breakpoint
+	layout
+	BaseElement
+}
+
+// NewGrid creates an empty Grid with the given number of columns
+func NewGrid(name string, columns int) *Grid {
+	g := &Grid{ID: name, Columns: columns, Elements: &Elements{slice: []*Element{}}}
+	g.layout = newLayout(&g.BaseElement, "dali-grid")
+	return g
+}
+
+// String for Grid
+func (g *Grid) String() string {
+	return renderContainer(g.ID, g.StyleName, &g.BaseElement, g.Elements)
+}
+
+// rules adds the rules laying out the Grid to css
+func (g *Grid) rules(css *CSS) {
+	selector := g.selector(g.ID)
+	if selector == "" {
+		return
+	}
+	columns := g.Template
+	switch {
+	case columns != "":
+	case g.MinColumnWidth != "":
+		columns = fmt.Sprintf("repeat(auto-fill,minmax(%s,1fr))", g.MinColumnWidth)
+	case g.Columns > 0:
+		columns = fmt.Sprintf("repeat(%d,minmax(0,1fr))", g.Columns)
+	}
+	css.Rule(selector).Display("grid").Set("grid-template-columns", columns).Set("gap", g.Gap).
+		Set("align-items", string(g.Align)).Set("justify-items", string(g.Justify))
+	breakpoints(css, selector, g.Breakpoints)
+}
+
+// Name of the Grid
+func (g *Grid) Name() string { return g.ID }
+
+// Style of the Grid
+func (g *Grid) Style() string { return g.StyleName }
+
+// Styles of the Grid
+func (g *Grid) Styles() Styles { return parseStyles(g.StyleName) }
+
+// Children returns the Elements of the Grid
+func (g *Grid) Children() *Elements { return g.Elements }
+
+// SetStyle sets a CSS property of the Grid, or removes it if value is empty
+func (g *Grid) SetStyle(property, value string) error {
+	return g.live.setStyle(&g.StyleName, property, value)
+}
+
+// Show displays the Grid
+func (g *Grid) Show() error { return g.live.show(&g.StyleName) }
+
+// Hide hides the Grid
+func (g *Grid) Hide() error { return g.live.hide(&g.StyleName) }
+
+// Stack lays its Elements over one another, the last on top, such as a
+// canvas with controls floating over it
+type Stack struct {
+	ID          string
+	StyleName   string
+	Elements    *Elements
+	Align       Align // places children vertically
+	Justify     Align // places children horizontally
+	Breakpoints []Breakpoint
+	layout
+	BaseElement
+}
+
+// NewStack creates an empty Stack
+func NewStack(name string) *Stack {
+	s := &Stack{ID: name, Elements: &Elements{slice: []*Element{}}}
+	s.layout = newLayout(&s.BaseElement, "dali-stack")
+	return s
+}
+
+// String for Stack
+func (s *Stack) String() string {
+	return renderContainer(s.ID, s.StyleName, &s.BaseElement, s.Elements)
+}
+
+// rules adds the rules laying out the Stack to css
+func (s *Stack) rules(css *CSS) {
+	selector := s.selector(s.ID)
+	if selector == "" {
+		return
+	}
+	css.Rule(selector).Display("grid").Set("align-items", string(s.Align)).Set("justify-items", string(s.Justify))
+	css.Rule(selector+">*").Set("grid-area", "1/1")
+	breakpoints(css, selector, s.Breakpoints)
+}
+
+// Name of the Stack
+func (s *Stack) Name() string { return s.ID }
+
+// Style of the Stack
+func (s *Stack) Style() string { return s.StyleName }
+
+// Styles of the Stack
+func (s *Stack) Styles() Styles { return parseStyles(s.StyleName) }
+
+// Children returns the Elements of the Stack
+func (s *Stack) Children() *Elements { return s.Elements }
+
+// SetStyle sets a CSS property of the Stack, or removes it if value is empty
+func (s *Stack) SetStyle(property, value string) error {
+	return s.live.setStyle(&s.StyleName, property, value)
+}
+
+// Show displays the Stack
+func (s *Stack) Show() error { return s.live.show(&s.StyleName) }
+
+// Hide hides the Stack
+func (s *Stack) Hide() error { return s.live.hide(&s.StyleName) }
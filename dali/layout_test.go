@@ -0,0 +1,64 @@
+package dali
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLayoutRules(t *testing.T) {
+	row := NewRow("toolbar")
+	row.Gap, row.Align, row.Wrap = Px(8), AlignCenter, true
+	row.Breakpoints = []Breakpoint{{MinWidth: 600, Direction: "column", Gap: Px(4)}}
+	grid := NewGrid("cards", 3)
+	grid.Breakpoints = []Breakpoint{{MinWidth: 900, Columns: 4}, {MinWidth: 1200, Hidden: true}}
+	fitted := &Grid{ID: `a"b`, MinColumnWidth: Px(100), Elements: &Elements{slice: []*Element{}}}
+	stack := NewStack("scene")
+	column := NewColumn("side")
+	column.Justify = AlignEnd
+	row.Elements.AddElement(grid)
+	grid.Elements.AddElement(fitted)
+	w := NewWindow(100, 100, "", "")
+	if w.layoutCSS() != "" {
+		t.Errorf("expected a Window without layout containers to add no layout rules")
+	}
+	w.Elements.AddElement(row)
+	w.Elements.AddElement(stack)
+	w.Elements.AddElement(column)
+
+	for _, expected := range []string{`<div id="toolbar" class="` + row.scope + `">`, `<div id="cards" class="` + grid.scope + `">`} {
+		if html := row.String(); !strings.Contains(html, expected) {
+			t.Errorf(`expected "%s" in "%s"`, expected, html)
+		}
+	}
+	css := w.layoutCSS()
+	for _, expected := range []string{
+		"." + row.scope + "{align-items:center;display:flex;flex-direction:row;flex-wrap:wrap;gap:8px;}",
+		"." + grid.scope + "{display:grid;grid-template-columns:repeat(3,minmax(0,1fr));}",
+		`[id="a\"b"]{display:grid;grid-template-columns:repeat(auto-fill,minmax(100px,1fr));}`,
+		"." + stack.scope + "{display:grid;}." + stack.scope + ">*{grid-area:1/1;}",
+		"." + column.scope + "{display:flex;flex-direction:column;justify-content:end;}",
+		"@media (min-width: 600px){." + row.scope + "{flex-direction:column;gap:4px;}}",
+		"@media (min-width: 900px){." + grid.scope + "{grid-template-columns:repeat(4,minmax(0,1fr));}}",
+		"@media (min-width: 1200px){." + grid.scope + "{display:none;}}",
+	} {
+		if !strings.Contains(css, expected) {
+			t.Errorf(`expected the layout rules to hold "%s" but got "%s"`, expected, css)
+		}
+	}
+	if !strings.HasSuffix(w.head(), css) {
+		t.Errorf("expected the layout rules to come last in the head")
+	}
+}
+
+func TestLayoutChangesReachThePage(t *testing.T) {
+	grid := NewGrid("cards", 2)
+	w := startedWindow(t, &fakeUI{}, grid)
+	grid.Columns = 5
+	if err := w.Update(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(w.head(), "repeat(5,minmax(0,1fr))") {
+		t.Errorf("expected the grid to have five columns")
+	}
+	inStep(t, w, "changing the columns of a grid")
+}
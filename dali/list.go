@@ -0,0 +1,201 @@
+package dali
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// List is an unordered (ul) or ordered (ol) list of ListItems
+type List struct {
+	ID        string
+	Ordered   bool
+	Start     int // the number of the first item of an ordered list, when not 1
+	StyleName string
+	Elements  *Elements
+	items     map[string]listed
+	key       func(item interface{}) string
+	build     func(item interface{}) Element
+	BaseElement
+}
+
+// listed is the ListItem showing an item of a ListOf
+type listed struct {
+	li *ListItem
+	el *Element
+}
+
+// NewUL creates an empty unordered List
+func NewUL(name string) *List {
+	return &List{ID: name, Elements: &Elements{slice: []*Element{}}}
+}
+
+// NewOL creates an empty ordered List
+func NewOL(name string) *List {
+	return &List{ID: name, Ordered: true, Elements: &Elements{slice: []*Element{}}}
+}
+
+// ListOf creates an unordered List showing each item of slice as the element
+// made by build, in a ListItem keyed by key.  SetItems changes the items.
+func ListOf(name string, slice interface{}, key func(item interface{}) string, build func(item interface{}) Element) (*List, error) {
+	l := NewUL(name)
+	l.key, l.build = key, build
+	if err := l.SetItems(slice); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// String for List
+func (l *List) String() string {
+	tag := "ul"
+	attrs := attribute("id", l.ID)
+	if l.Ordered {
+		tag = "ol"
+		if l.Start != 0 && l.Start != 1 {
+			attrs += attribute("start", strconv.Itoa(l.Start))
+		}
+	}
+	if l.StyleName != "" {
+		attrs += attribute("style", l.StyleName)
+	}
+	return fmt.Sprintf(`<%s%s%s%s>%s</%s>`, tag, attrs, l.attributes(), l.Events.attributes(), l.Elements, tag)
+}
+
+// Name of the List
+func (l *List) Name() string { return l.ID }
+
+// Style of the List
+func (l *List) Style() string { return l.StyleName }
+
+// Styles of the List
+func (l *List) Styles() Styles { return parseStyles(l.StyleName) }
+
+// Children returns the ListItems of the List
+func (l *List) Children() *Elements { return l.Elements }
+
+// SetItems shows the items of slice in a List made by ListOf.  Items whose key
+// was shown before keep their ListItem, with its contents built again from the
+// item, so an item changed in place shows its changes.  Once the Window is
+// running only the ListItems that were added, removed, moved or changed are
+// changed in the page.
+func (l *List) SetItems(slice interface{}) error {
+	if l.build == nil || l.key == nil {
+		return fmt.Errorf("List %s was not made by ListOf", l.ID)
+	}
+	r := reflect.ValueOf(slice)
+	if r.Kind() != reflect.Slice && r.Kind() != reflect.Array {
+		return fmt.Errorf("SetItems needs a slice, not %T", slice)
+	}
+	items := map[string]listed{}
+	elements := []*Element{}
+	for i := 0; i < r.Len(); i++ {
+		item := r.Index(i).Interface()
+		key := l.key(item)
+		if _, ok := items[key]; ok {
+			return fmt.Errorf("List %s has two items with the key %q", l.ID, key)
+		}
+		old, ok := l.items[key]
+		if ok && old.li.Elements.Len() == 1 {
+			// the content keeps its place, and so its marker, so only its changes are patched
+			*old.li.Elements.slice[0] = l.build(item)
+		} else if ok {
+			var e Element = l.build(item)
+			old.li.Elements.slice = []*Element{&e}
+		} else {
+			var e Element = NewListItem(key, l.build(item))
+			old = listed{li: e.(*ListItem), el: &e}
+		}
+		items[key] = old
+		elements = append(elements, old.el)
+	}
+	l.items = items
+	l.Elements.slice = elements
+	if !l.live.running() {
+		return nil
+	}
+	return l.live.window.Update()
+}
+
+// SetStyle sets a CSS property of the List, or removes it if value is empty
+func (l *List) SetStyle(property, value string) error {
+	return l.live.setStyle(&l.StyleName, property, value)
+}
+
+// Show displays the List
+func (l *List) Show() error { return l.live.show(&l.StyleName) }
+
+// Hide hides the List
+func (l *List) Hide() error { return l.live.hide(&l.StyleName) }
+
+// ListItem is an item (li) of a List.  Its Key identifies it among the items,
+// so an Update moves it rather than rebuilding it when the items are reordered.
+type ListItem struct {
+	ID        string
+	Key       string
+	StyleName string
+	Elements  *Elements
+	BaseElement
+}
+
+// NewListItem creates a ListItem holding els
+func NewListItem(key string, els ...Element) *ListItem {
+	li := &ListItem{Key: key, Elements: &Elements{slice: []*Element{}}}
+	for _, e := range els {
+		e := e
+		li.Elements.slice = append(li.Elements.slice, &e)
+	}
+	return li
+}
+
+// String for ListItem
+func (li *ListItem) String() string {
+	attrs := ""
+	if li.ID != "" {
+		attrs += attribute("id", li.ID)
+	}
+	if li.Key != "" {
+		attrs += attribute("data-key", li.Key)
+	}
+	if li.StyleName != "" {
+		attrs += attribute("style", li.StyleName)
+	}
+	return fmt.Sprintf(`<li%s%s%s>%s</li>`, attrs, li.attributes(), li.Events.attributes(), li.Elements)
+}
+
+// Name of the ListItem
+func (li *ListItem) Name() string { return li.ID }
+
+// Style of the ListItem
+func (li *ListItem) Style() string { return li.StyleName }
+
+// Styles of the ListItem
+func (li *ListItem) Styles() Styles { return parseStyles(li.StyleName) }
+
+// Children returns the Elements of the ListItem
+func (li *ListItem) Children() *Elements { return li.Elements }
+
+// SetText replaces the contents of the ListItem with text
+func (li *ListItem) SetText(text string) error {
+	var t Element = Text(text)
+	li.Elements.slice = []*Element{&t}
+	return li.live.setHTML(li.Elements.String())
+}
+
+// SetHTML replaces the contents of the ListItem with markup
+func (li *ListItem) SetHTML(html string) error {
+	var t Element = RawHTML(html)
+	li.Elements.slice = []*Element{&t}
+	return li.live.setHTML(li.Elements.String())
+}
+
+// SetStyle sets a CSS property of the ListItem, or removes it if value is empty
+func (li *ListItem) SetStyle(property, value string) error {
+	return li.live.setStyle(&li.StyleName, property, value)
+}
+
+// Show displays the ListItem
+func (li *ListItem) Show() error { return li.live.show(&li.StyleName) }
+
+// Hide hides the ListItem
+func (li *ListItem) Hide() error { return li.live.hide(&li.StyleName) }
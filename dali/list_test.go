@@ -0,0 +1,62 @@
+package dali
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type todo struct {
+	ID, Title string
+}
+
+func TestSetItemsShowsItemsChangedInPlace(t *testing.T) {
+	items := []*todo{{"1", "milk"}, {"2", "eggs"}}
+	key := func(item interface{}) string { return item.(*todo).ID }
+	build := func(item interface{}) Element { return Text(item.(*todo).Title) }
+	l, err := ListOf("todos", items, key, build)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := l.items["1"].li
+
+	items[0].Title = "bread"
+	if err := l.SetItems(items); err != nil {
+		t.Fatal(err)
+	}
+	if html := fmt.Sprintf("%s", l); !strings.Contains(html, "bread") || strings.Contains(html, "milk") {
+		t.Errorf(`expected "%s" but got "%s"`, "bread", html)
+	}
+	if l.items["1"].li != first {
+		t.Errorf("expected the ListItem of the changed item to be kept")
+	}
+}
+
+func TestSetItemsPatchesOnlyChangedItems(t *testing.T) {
+	items := []*todo{{"1", "milk"}, {"2", "eggs"}}
+	key := func(item interface{}) string { return item.(*todo).ID }
+	build := func(item interface{}) Element { return Text(item.(*todo).Title) }
+	l, err := ListOf("todos", items, key, build)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ui := &fakeUI{}
+	startedWindow(t, ui, l)
+	shown := len(ui.evals)
+
+	if err := l.SetItems(items); err != nil {
+		t.Fatal(err)
+	}
+	if patched := ui.evals[shown:]; len(patched) != 0 {
+		t.Errorf("expected unchanged items to be left alone but got %v", patched)
+	}
+
+	items[0].Title = "bread"
+	if err := l.SetItems(items); err != nil {
+		t.Fatal(err)
+	}
+	expected := `dali.patch([{"op":"text","path":[1,0,1,1],"value":"bread"}])`
+	if patched := ui.evals[shown:]; len(patched) != 1 || patched[0] != expected {
+		t.Errorf(`expected "%s" but got %v`, expected, patched)
+	}
+}
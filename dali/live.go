@@ -0,0 +1,194 @@
+package dali
+
+import (
+	"fmt"
+	"strings"
+)
+
+// live holds what an element needs to change itself after its Window has
+// started: every change is made to the Go element and, once running, to the page
+type live struct {
+	window *Window
+	self   Element
+}
+
+// setWindow binds the element to the Window it is rendered in.  An element
+// already bound is left alone, as its handlers may be reading the Window.
+func (l *live) setWindow(w *Window, self Element) {
+	if l.window == w && l.self == self {
+		return
+	}
+	l.window = w
+	l.self = self
+}
+
+// running is true once the Window showing the element has started
+func (l *live) running() bool {
+	return l.window != nil && l.window.started() != nil
+}
+
+// eval runs script in the page with el bound to the element.  Each %s in the
+// script is replaced by the matching argument, encoded as a JavaScript literal.
+// It does nothing until the Window is running.
+func (l *live) eval(script string, args ...interface{}) error {
+	if !l.running() {
+		return nil
+	}
+	id := l.self.Name()
+	if id == "" {
+		return fmt.Errorf("%T has no ID to find it in the page", l.self)
+	}
+	target, err := jsArgs(id)
+	if err != nil {
+		return err
+	}
+	literals := make([]interface{}, len(args))
+	for i, arg := range args {
+		if literals[i], err = jsArgs(arg); err != nil {
+			return err
+		}
+	}
+	return l.window.started().Eval(fmt.Sprintf(`(function(el){%s})(document.getElementById(%s))`,
+		fmt.Sprintf(script, literals...), target)).Err()
+}
+
+// change runs script like eval, then makes the same change with mirror to the
+// node of the element in the tree the page was sent, so the next Update
+// compares against what the page shows without rendering it all again
+func (l *live) change(mirror func(t *tree, n *vnode), script string, args ...interface{}) error {
+	if !l.running() {
+		return nil
+	}
+	w := l.window
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if err := l.eval(script, args...); err != nil {
+		return err
+	}
+	if w.rendered != nil {
+		if n := w.rendered.ids[l.self.Name()]; n != nil {
+			mirror(w.rendered, n)
+		}
+	}
+	return nil
+}
+
+// mirror makes a change the user has made in the page to the node of the
+// element in the tree the page was sent
+func (l *live) mirror(f func(t *tree, n *vnode)) {
+	if !l.running() {
+		return
+	}
+	w := l.window
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.rendered != nil {
+		if n := w.rendered.ids[l.self.Name()]; n != nil {
+			f(w.rendered, n)
+		}
+	}
+}
+
+// mirrorAttr is a mirror setting an attribute, or removing it when value is empty
+func mirrorAttr(name, value string) func(t *tree, n *vnode) {
+	return func(t *tree, n *vnode) {
+		if value == "" {
+			n.removeAttr(name)
+		} else {
+			n.setAttr(name, value)
+		}
+	}
+}
+
+// setStyle sets or, when value is empty, removes a property of the style
+func (l *live) setStyle(style *string, property, value string) error {
+	*style = setStyleProperty(*style, property, value)
+	if value == "" {
+		return l.change(mirrorAttr("style", *style), `el.style.removeProperty(%s);`, property)
+	}
+	return l.change(mirrorAttr("style", *style), `el.style.setProperty(%s,%s);`, property, value)
+}
+
+// show clears display and visibility from the style so the element is shown
+func (l *live) show(style *string) error {
+	*style = setStyleProperty(setStyleProperty(*style, "visibility", ""), "display", "")
+	return l.change(mirrorAttr("style", *style), `el.style.removeProperty("visibility");el.style.removeProperty("display");`)
+}
+
+// hide sets display:none on the style
+func (l *live) hide(style *string) error {
+	return l.setStyle(style, "display", "none")
+}
+
+// setText replaces the content of the element with text
+func (l *live) setText(text string) error {
+	return l.change(func(t *tree, n *vnode) { t.setChildren(n, textNodes(text)...) }, `el.textContent=%s;`, text)
+}
+
+// setHTML replaces the content of the element with markup
+func (l *live) setHTML(html string) error {
+	return l.change(func(t *tree, n *vnode) { t.setChildren(n, parseHTML(html)...) }, `el.innerHTML=%s;`, html)
+}
+
+// patch brings the element in the page up to date with render, sending the
+// differences from what the page was sent for it.  Only the element is
+// rendered and compared, not the whole Window.  An element without an ID
+// cannot be found in the page, so it updates the Window instead.
+func (l *live) patch(render func() string) error {
+	if !l.running() {
+		return nil
+	}
+	w := l.window
+	id := l.self.Name()
+	if id == "" {
+		return w.Update()
+	}
+	w.updating.Lock()
+	defer w.updating.Unlock()
+	nodes := parseHTML(render())
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.rendered == nil || len(nodes) != 1 {
+		return nil
+	}
+	// an element not in the page yet is sent with the next Update
+	old := w.rendered.ids[id]
+	if old == nil {
+		return nil
+	}
+	patches := diff(old, nodes[0], old.path(), []patch{})
+	if len(patches) > 0 {
+		if err := Call(w.started(), "dali.patch", patches).Err(); err != nil {
+			return err
+		}
+	}
+	w.rendered.replace(old, nodes[0])
+	return nil
+}
+
+// setStyleProperty sets property in an inline style declaration, removing it if value is empty
+func setStyleProperty(style, property, value string) string {
+	declarations := []string{}
+	found := false
+	for _, d := range strings.Split(style, ";") {
+		if strings.TrimSpace(d) == "" {
+			continue
+		}
+		kv := strings.SplitN(d, ":", 2)
+		if strings.TrimSpace(kv[0]) == property {
+			found = true
+			if value == "" {
+				continue
+			}
+			d = fmt.Sprintf("%s:%s", property, value)
+		}
+		declarations = append(declarations, strings.TrimSpace(d))
+	}
+	if !found && value != "" {
+		declarations = append(declarations, fmt.Sprintf("%s:%s", property, value))
+	}
+	if len(declarations) == 0 {
+		return ""
+	}
+	return strings.Join(declarations, ";") + ";"
+}
@@ -0,0 +1,45 @@
+package dali
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLiveChangesReachThePage(t *testing.T) {
+	s := &Span{ID: "title", Text: "Hello"}
+	if err := s.SetText("before"); err != nil || s.Text != "before" {
+		t.Errorf(`expected "%s" before the Window starts but got "%s" and %v`, "before", s.Text, err)
+	}
+	ui := &fakeUI{}
+	w := startedWindow(t, ui, s)
+
+	steps := []struct {
+		name     string
+		change   func() error
+		script   string
+		expected string
+	}{
+		{"SetText", func() error { return s.SetText("a <b>") }, `el.textContent="a \u003cb\u003e";`, `>a &lt;b&gt;</span>`},
+		{"SetHTML", func() error { return s.SetHTML("<b>bold</b>") }, `el.innerHTML="\u003cb\u003ebold\u003c/b\u003e";`, `><b>bold</b></span>`},
+		{"SetStyle", func() error { return s.SetStyle("color", "red") }, `el.style.setProperty("color","red");`, ` style="color:red;"`},
+		{"Hide", s.Hide, `el.style.setProperty("display","none");`, ` style="color:red;display:none;"`},
+		{"Show", s.Show, `el.style.removeProperty("visibility");el.style.removeProperty("display");`, ` style="color:red;"`},
+		{"SetAttr", func() error { return s.SetTitle("a tip") }, `el.setAttribute("title","a tip");`, ` title="a tip"`},
+		{"AddClass", func() error { return s.AddClass("big") }, `el.classList.add("big");`, ` class="big"`},
+		{"RemoveClass", func() error { return s.RemoveClass("big") }, `el.classList.remove("big");`, `<span id="title" style="color:red;" title="a tip">`},
+		{"Focus", s.Focus, `el.focus();`, ``},
+	}
+	for _, step := range steps {
+		before := len(ui.evals)
+		if err := step.change(); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		if evals := ui.evals[before:]; len(evals) != 1 || !strings.Contains(evals[0], step.script) {
+			t.Errorf(`%s: expected "%s" to be run but got %v`, step.name, step.script, evals)
+		}
+		if html := s.String(); !strings.Contains(html, step.expected) {
+			t.Errorf(`%s: expected "%s" but got "%s"`, step.name, step.expected, html)
+		}
+		inStep(t, w, step.name)
+	}
+}
@@ -0,0 +1,362 @@
+package dali
+
+/**
+* Copyright (c)2020, Matthew A Peters
+ */
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/zserge/lorca"
+)
+
+// Styles is a map of style elements and values
+type Styles map[string]string
+
+//String for Styles, with the properties sorted so the same Styles always render the same
+func (s Styles) String() string {
+	properties := make([]string, 0, len(s))
+	for k := range s {
+		properties = append(properties, k)
+	}
+	sort.Strings(properties)
+	style := ""
+	for _, k := range properties {
+		style = fmt.Sprintf("%s%s:%s;", style, k, s[k])
+	}
+	return style
+}
+
+// parseStyles reads an inline style declaration into Styles
+func parseStyles(style string) Styles {
+	s := Styles{}
+	for _, d := range strings.Split(style, ";") {
+		kv := strings.SplitN(d, ":", 2)
+		if len(kv) == 2 && strings.TrimSpace(kv[0]) != "" {
+			s[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+		}
+	}
+	return s
+}
+
+//Element is an interface for describing an HTML element
+type Element interface {
+	String() string
+	Class() string
+	Style() string
+	Name() string
+	Clickable() bool
+	Styles() Styles
+	Children() *Elements
+	Bindings() *Binding
+}
+
+//Elements is a slice of Elements
+type Elements struct {
+	slice    []*Element
+	window   *Window
+	owner    Element
+	keys     map[*Element]string
+	keysLock sync.Mutex
+}
+
+//String for Elements, with a marker comment before each element so its nodes
+//can be found in the page, and one after the last.  No Elements renders as nothing.
+func (els *Elements) String() string {
+	if els == nil {
+		return ""
+	}
+	html := ""
+	for i, key := range els.markers() {
+		html = fmt.Sprintf(`%s<!--%s-->%s`, html, key, *els.slice[i])
+	}
+
+	return fmt.Sprintf(`%s<!--%s-->`, html, endMarker)
+}
+
+// AddElement appends an element to the slice of elements.  Once the Window is
+// running the element is added to the page as well: use Insert to see any error.
+func (els *Elements) AddElement(e Element) {
+	els.Insert(len(els.slice), e)
+}
+
+// Binding defines which JavaScript functions should be bound to Go functions
+type Binding struct {
+	FunctionName  string
+	BoundFunction func()
+}
+
+// Window is the main application window
+type Window struct {
+	Width, Height int
+	Style         StyleSheet
+	html          string
+	ui            lorca.UI
+	ProfileDir    string
+	Elements      *Elements
+	Args          []string
+	Bindings      []Binding
+	handlers      map[string]interface{}
+	bound         map[string]bool
+	rendered      *tree
+	css           []*CSS
+	stylesheets   []*StyleSheet
+	themes        []*Theme
+	theme         string
+	followLight   string
+	followDark    string
+	onColorScheme []func(dark bool)
+	dark          bool // the system color scheme last seen
+	schemeKnown   bool
+	themeLock     sync.Mutex
+	lock          sync.Mutex
+	uiLock        sync.Mutex
+	// updating is held while the elements are attached, their functions bound
+	// and the page patched.  lorca calls each bound function in a goroutine of
+	// its own, so handlers can update the Window at the same time.
+	updating sync.Mutex
+}
+
+// NewWindow creates a new Window
+func NewWindow(width, height int, profileDir string, styleSheet string, args ...string) *Window {
+
+	els := Elements{slice: []*Element{}}
+
+	w := Window{
+		Width:      width,
+		Height:     height,
+		Style:      windowStyleSheet(styleSheet),
+		ui:         nil,
+		Args:       args,
+		ProfileDir: profileDir,
+		Elements:   &els,
+		Bindings:   []Binding{},
+		handlers:   map[string]interface{}{},
+	}
+	return &w
+}
+
+//String for Window, adding its CSS to the HeadElement, or to a head of its own if there is none
+func (w *Window) String() string {
+	head := w.head()
+	html := ""
+	for _, el := range w.Elements.slice {
+		if h, ok := (*el).(*HeadElement); ok && head != "" {
+			html = fmt.Sprintf(`%s%s`, html, h.render(head))
+			head = ""
+			continue
+		}
+		html = fmt.Sprintf(`%s%s`, html, *el)
+	}
+	if head != "" {
+		html = fmt.Sprintf(`<head>%s</head>%s`, head, html)
+	}
+	return fmt.Sprintf(`<html%s>%s</html>`, w.themeAttribute(), html)
+}
+
+//Bind maps a javascript function to a golang function.  Binding a name again
+//replaces its function, in the page as well once it is bound there.
+func (w *Window) Bind(jscriptFunction string, golangFunction func()) {
+	w.updating.Lock()
+	defer w.updating.Unlock()
+	w.bind(jscriptFunction, golangFunction)
+}
+
+// bind is Bind for a Window that is updating
+func (w *Window) bind(jscriptFunction string, golangFunction func()) {
+	for i, b := range w.Bindings {
+		if b.FunctionName == jscriptFunction {
+			w.Bindings[i].BoundFunction = golangFunction
+			// lorca swaps the function of a name bound again, so it is bound on the next bindPending
+			delete(w.bound, jscriptFunction)
+			return
+		}
+	}
+	w.Bindings = append(
+		w.Bindings, Binding{FunctionName: jscriptFunction,
+			BoundFunction: golangFunction})
+}
+
+// handlerBinder is implemented by elements whose bound Go functions take
+// arguments from the page, such as the event data of a PointerEvent
+type handlerBinder interface {
+	boundHandlers() map[string]interface{}
+}
+
+//BindChildren is used to recursively
+func (w *Window) BindChildren(el *Element) {
+	w.updating.Lock()
+	defer w.updating.Unlock()
+	w.bindChildren(el)
+}
+
+// bindChildren is BindChildren for a Window that is updating
+func (w *Window) bindChildren(el *Element) {
+
+	if el == nil {
+		for _, el := range w.Elements.slice {
+			w.bindChildren((el))
+		}
+		return
+	}
+
+	b := (*el).Bindings()
+	if b != nil {
+		if b.BoundFunction != nil {
+			w.bind(b.FunctionName, b.BoundFunction)
+		}
+
+	}
+	if h, ok := (*el).(handlerBinder); ok {
+		if w.handlers == nil {
+			w.handlers = map[string]interface{}{}
+		}
+		for name, f := range h.boundHandlers() {
+			w.handlers[name] = f
+			delete(w.bound, name)
+		}
+	}
+	if els := (*el).Children(); els != nil {
+		for _, c := range els.slice {
+			w.bindChildren(c)
+		}
+	}
+}
+
+// windowed is implemented by elements that talk to the running page
+type windowed interface {
+	setWindow(w *Window, self Element)
+}
+
+// starter is implemented by elements that set themselves up before they are first rendered
+type starter interface {
+	start() error
+}
+
+// start sets up el and every element below it before they are rendered
+func start(el Element) error {
+	if s, ok := el.(starter); ok {
+		if err := s.start(); err != nil {
+			return err
+		}
+	}
+	if children := el.Children(); children != nil {
+		for _, c := range children.slice {
+			if err := start(*c); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+//attach hands the Window to el and every element below it
+func (w *Window) attach(el Element) {
+	if e, ok := el.(windowed); ok {
+		e.setWindow(w, el)
+	}
+	if children := el.Children(); children != nil {
+		if children.window != w || children.owner != el {
+			children.window = w
+			children.owner = el
+		}
+		for _, c := range children.slice {
+			w.attach(*c)
+		}
+	}
+}
+
+//bindPending binds the functions of Bindings and handlers that are not yet bound in the page
+func (w *Window) bindPending() error {
+	if w.bound == nil {
+		w.bound = map[string]bool{}
+	}
+	for _, b := range w.Bindings {
+		if w.bound[b.FunctionName] {
+			continue
+		}
+		if !jsFunction.MatchString(b.FunctionName) {
+			return fmt.Errorf("%q is not a JavaScript function name", b.FunctionName)
+		}
+		if err := w.started().Bind(b.FunctionName, b.BoundFunction); err != nil {
+			return err
+		}
+		w.bound[b.FunctionName] = true
+	}
+	for name, f := range w.handlers {
+		if w.bound[name] {
+			continue
+		}
+		if err := w.started().Bind(name, f); err != nil {
+			return err
+		}
+		w.bound[name] = true
+	}
+	return nil
+}
+
+// Start extracts the application HTML and starts the UI
+func (w *Window) Start() error {
+	for _, el := range w.Elements.slice {
+		if err := start(*el); err != nil {
+			return err
+		}
+	}
+	html := w.String()
+	newui, err := lorca.New("data:text/html,"+url.PathEscape(html), w.ProfileDir, w.Width, w.Height, w.Args...)
+	if err != nil {
+		return err
+	}
+	return w.run(newui, html)
+}
+
+// run binds the Window to ui, which has loaded the page html
+func (w *Window) run(newui lorca.UI, html string) error {
+	w.updating.Lock()
+	w.lock.Lock()
+	w.rendered = newTree(normalizeDocument(parseHTML(html)))
+	w.lock.Unlock()
+	w.uiLock.Lock()
+	w.ui = newui
+	w.uiLock.Unlock()
+
+	w.Elements.window = w
+	for _, el := range w.Elements.slice {
+		w.attach(*el)
+	}
+	w.bindChildren(nil)
+	if w.handlers == nil {
+		w.handlers = map[string]interface{}{}
+	}
+	w.handlers["dali_open"] = OpenURL
+	w.handlers["dali_color_scheme"] = w.colorSchemeChanged
+
+	//Apply Bindings
+	err := w.bindPending()
+	w.updating.Unlock()
+	if err != nil {
+		return err
+	}
+	return w.followSystemTheme()
+}
+
+// started is the lorca.UI of the Window once it has started, or nil before
+func (w *Window) started() lorca.UI {
+	w.uiLock.Lock()
+	defer w.uiLock.Unlock()
+	return w.ui
+}
+
+//Close wraps lorca.UI.Close()
+func (w *Window) Close() {
+	w.started().Close()
+}
+
+//GetUI is a temporary wrapper for retrieving the lorca.UI
+func (w *Window) GetUI() lorca.UI {
+	return w.started()
+}
@@ -0,0 +1,75 @@
+package dali
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseStyles(t *testing.T) {
+	styles := parseStyles(" color: red ;background:url(http://example.com/a.png); ;broken; width:10px")
+	expected := Styles{"color": "red", "background": "url(http://example.com/a.png)", "width": "10px"}
+	if !reflect.DeepEqual(styles, expected) {
+		t.Errorf(`expected %v but got %v`, expected, styles)
+	}
+	if style := parseStyles("width:10px;color:red").String(); style != "color:red;width:10px;" {
+		t.Errorf(`expected "%s" but got "%s"`, "color:red;width:10px;", style)
+	}
+}
+
+func TestElementsMadeWithoutChildren(t *testing.T) {
+	div, row := &Div{ID: "d"}, &Row{ID: "r"}
+	if html := row.String(); strings.Contains(html, "nil") || !strings.HasPrefix(html, `<div id="r"`) {
+		t.Errorf(`expected an empty Row but got "%s"`, html)
+	}
+	w := startedWindow(t, &fakeUI{}, div, row)
+	if html := w.Elements.String(); strings.Contains(html, "nil") {
+		t.Errorf(`expected empty elements but got "%s"`, html)
+	}
+}
+
+// badge is a custom element written with only String and Name
+type badge struct {
+	ID    string
+	Count int
+	BaseElement
+}
+
+func (b *badge) String() string {
+	return fmt.Sprintf(`<b id="%s"%s%s>%d</b>`, escape(b.ID), b.attributes(), b.Events.attributes(), b.Count)
+}
+
+func (b *badge) Name() string { return b.ID }
+
+func TestBaseElementDefaults(t *testing.T) {
+	b := &badge{ID: "count", Count: 3}
+	b.On("click", func(Event) (interface{}, error) { return nil, nil })
+	w := startedWindow(t, &fakeUI{}, b)
+	if b.Class() != "" || b.Style() != "" || b.Clickable() || len(b.Styles()) != 0 || b.Children().Len() != 0 || b.Bindings() != nil {
+		t.Errorf("expected empty defaults for a custom element")
+	}
+	if err := b.AddClass("hot"); err != nil {
+		t.Fatal(err)
+	}
+	inStep(t, w, "adding a class to a custom element")
+
+	elements := []Element{&Anchor{}, &BR{}, &Header{}, &Button{}, &Canvas{}, &Div{}, &Form{}, &Label{},
+		&HeadElement{}, &ScriptElement{}, &TitleElement{}, &BodyElement{}, &Image{}, &Input{}, &Row{},
+		&Column{}, &Grid{}, &Stack{}, &List{}, &ListItem{}, &Router{}, &Select{}, &DataList{}, &Span{},
+		&Table{}, &TabSet{}}
+	for _, el := range elements {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					t.Errorf("expected a %T made without its constructor to be safe but it panics: %v", el, r)
+				}
+			}()
+			_ = el.String() + el.Class() + el.Style() + el.Name()
+			el.Clickable()
+			el.Styles()
+			el.Children()
+			el.Bindings()
+		}()
+	}
+}
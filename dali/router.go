@@ -0,0 +1,384 @@
+package dali
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Params are the values of the parameters of a route, by name: /users/:id
+// matched by /users/42 has the id "42", and a final * has the rest of the path.
+type Params map[string]string
+
+// PageBuilder builds the page shown for a route
+type PageBuilder func(params Params) Element
+
+// Route is a path pattern, such as /users/:id, and the page shown for it
+type Route struct {
+	Name    string
+	Pattern string
+	build   PageBuilder
+	enter   []func(Params)
+	leave   []func(Params)
+}
+
+// OnEnter calls f with the params whenever the route is shown
+func (rt *Route) OnEnter(f func(Params)) *Route {
+	rt.enter = append(rt.enter, f)
+	return rt
+}
+
+// OnLeave calls f with the params the route was shown with whenever it is left
+func (rt *Route) OnLeave(f func(Params)) *Route {
+	rt.leave = append(rt.leave, f)
+	return rt
+}
+
+// match returns the params of path if it matches the pattern of the route
+func (rt *Route) match(path string) (Params, bool) {
+	pattern := splitPath(rt.Pattern)
+	parts := splitPath(path)
+	params := Params{}
+	for i, p := range pattern {
+		if p == "*" {
+			params["*"] = strings.Join(parts[i:], "/")
+			return params, true
+		}
+		if i >= len(parts) {
+			return nil, false
+		}
+		if strings.HasPrefix(p, ":") {
+			params[p[1:]] = parts[i]
+		} else if p != parts[i] {
+			return nil, false
+		}
+	}
+	return params, len(parts) == len(pattern)
+}
+
+// path fills the parameters of the pattern from params
+func (rt *Route) path(params Params) (string, error) {
+	parts := []string{}
+	for _, p := range splitPath(rt.Pattern) {
+		switch {
+		case p == "*" || strings.HasPrefix(p, ":"):
+			name := strings.TrimPrefix(p, ":")
+			value, ok := params[name]
+			if !ok {
+				return "", fmt.Errorf("route %s needs the parameter %s", rt.Pattern, name)
+			}
+			if p == "*" {
+				parts = append(parts, splitPath(value)...)
+				continue
+			}
+			parts = append(parts, value)
+		default:
+			parts = append(parts, p)
+		}
+	}
+	return "/" + strings.Join(parts, "/"), nil
+}
+
+// splitPath splits a path into its non-empty segments
+func splitPath(path string) []string {
+	parts := []string{}
+	for _, p := range strings.Split(path, "/") {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return parts
+}
+
+// Router shows one page at a time, chosen by the path after the # of the page
+// address, so that Anchors made with NewRouteLink switch pages.  It keeps its
+// own history for Back and Forward.  Render the Router where its pages should
+// appear: it is a div holding the current page.  The Home page is shown when
+// the Window starts, or when the Router is added to a running Window, unless
+// Navigate has shown another first.
+type Router struct {
+	ID        string
+	Home      string      // the path shown first, / by default
+	NotFound  PageBuilder // builds the page for a path no route matches
+	StyleName string
+	Elements  *Elements
+	routes    []*Route
+	current   *Route
+	params    Params
+	shown     string
+	history   []string
+	position  int
+	pending   []string // paths Go has sent the page to, which it has yet to report
+	change    string
+	lock      sync.Mutex
+	BaseElement
+}
+
+// NewRouter creates a Router with no routes
+func NewRouter(name string) *Router {
+	return &Router{
+		ID:       name,
+		Home:     "/",
+		Elements: &Elements{slice: []*Element{}},
+		position: -1,
+		change:   functionName("route"),
+	}
+}
+
+// Handle shows the page made by build for paths matching pattern.  Segments
+// starting with : are parameters and a final * matches the rest of the path.
+// Routes are tried in the order they were added.
+func (r *Router) Handle(pattern string, build PageBuilder) *Route {
+	rt := &Route{Pattern: pattern, build: build}
+	r.routes = append(r.routes, rt)
+	return rt
+}
+
+// HandleNamed is Handle for a route that Go can go to by name with NavigateTo
+func (r *Router) HandleNamed(name, pattern string, build PageBuilder) *Route {
+	rt := r.Handle(pattern, build)
+	rt.Name = name
+	return rt
+}
+
+// String for Router
+func (r *Router) String() string {
+	attrs := attribute("id", r.ID) + attribute("data-dali-router", r.function())
+	if r.StyleName != "" {
+		attrs += attribute("style", r.StyleName)
+	}
+	return fmt.Sprintf(`<div%s%s%s>%s</div>`, attrs, r.attributes(), r.Events.attributes(), r.Elements)
+}
+
+// start shows the Home page if no other has been shown yet
+func (r *Router) start() error {
+	r.lock.Lock()
+	started := r.position >= 0
+	r.lock.Unlock()
+	if started || !r.push(cleanPath(r.Home)) {
+		return nil
+	}
+	return r.show(cleanPath(r.Home))
+}
+
+// function is the name of the JavaScript function told when the path changes
+func (r *Router) function() string {
+	if r.change == "" {
+		r.change = functionName("route")
+	}
+	return r.change
+}
+
+// Name of the Router
+func (r *Router) Name() string { return r.ID }
+
+// Style of the Router
+func (r *Router) Style() string { return r.StyleName }
+
+// Styles of the Router
+func (r *Router) Styles() Styles { return parseStyles(r.StyleName) }
+
+// Children returns the page shown
+func (r *Router) Children() *Elements { return r.Elements }
+
+// Path is the path of the page shown
+func (r *Router) Path() string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.position < 0 {
+		return ""
+	}
+	return r.history[r.position]
+}
+
+// Params are the parameters of the route shown
+func (r *Router) Params() Params {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	params := Params{}
+	for k, v := range r.params {
+		params[k] = v
+	}
+	return params
+}
+
+// Navigate shows the page for path, adding it to the history
+func (r *Router) Navigate(path string) error {
+	path = cleanPath(path)
+	if !r.push(path) {
+		return nil
+	}
+	return r.open(path)
+}
+
+// push adds path to the history after the current page, unless it is shown already
+func (r *Router) push(path string) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if path == r.shown {
+		return false
+	}
+	r.history = append(r.history[:r.position+1], path)
+	r.position++
+	return true
+}
+
+// open shows the page for a path already placed in the history.  In a
+// running Window the page is sent there, and reports the change back to
+// changed, which shows it.
+func (r *Router) open(path string) error {
+	if !r.live.running() {
+		return r.show(path)
+	}
+	r.lock.Lock()
+	r.pending = append(r.pending, path)
+	r.lock.Unlock()
+	return r.live.eval(`location.hash=%s;`, path)
+}
+
+// NavigateTo shows the page of the route with the given name
+func (r *Router) NavigateTo(name string, params Params) error {
+	for _, rt := range r.routes {
+		if rt.Name == name {
+			path, err := rt.path(params)
+			if err != nil {
+				return err
+			}
+			return r.Navigate(path)
+		}
+	}
+	return fmt.Errorf("Router %s has no route named %s", r.ID, name)
+}
+
+// CanGoBack is true when there is a page before the current one in the history
+func (r *Router) CanGoBack() bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.position > 0
+}
+
+// CanGoForward is true when Back has been used and not followed by Navigate
+func (r *Router) CanGoForward() bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.position < len(r.history)-1
+}
+
+// Back shows the previous page in the history
+func (r *Router) Back() error { return r.step(-1) }
+
+// Forward shows the next page in the history, after Back
+func (r *Router) Forward() error { return r.step(1) }
+
+// step moves through the history by delta pages
+func (r *Router) step(delta int) error {
+	r.lock.Lock()
+	position := r.position + delta
+	if position < 0 || position >= len(r.history) {
+		r.lock.Unlock()
+		return nil
+	}
+	r.position = position
+	path := r.history[position]
+	r.lock.Unlock()
+	return r.open(path)
+}
+
+// changed shows the path the page reports.  A path Go sent the page to is in
+// the history already.  Any other comes from a link or the browser, and is
+// recorded in the history unless it is the next or previous one, as it is
+// after Back or Forward in the browser.
+func (r *Router) changed(path string) error {
+	path = cleanPath(path)
+	r.lock.Lock()
+	sent := false
+	for i, p := range r.pending {
+		if p == path {
+			r.pending, sent = r.pending[i+1:], true
+			break
+		}
+	}
+	if path == r.shown {
+		r.lock.Unlock()
+		return nil
+	}
+	switch {
+	case sent:
+	case r.position >= 0 && r.history[r.position] == path:
+	case r.position > 0 && r.history[r.position-1] == path:
+		r.position--
+	case r.position < len(r.history)-1 && r.history[r.position+1] == path:
+		r.position++
+	default:
+		r.history = append(r.history[:r.position+1], path)
+		r.position++
+	}
+	r.lock.Unlock()
+	return r.show(path)
+}
+
+// show replaces the page with the one for path, firing the leave and enter hooks
+func (r *Router) show(path string) error {
+	var route *Route
+	var params Params
+	for _, rt := range r.routes {
+		if p, ok := rt.match(path); ok {
+			route, params = rt, p
+			break
+		}
+	}
+	build := r.NotFound
+	if route != nil {
+		build = route.build
+	}
+
+	r.lock.Lock()
+	left, leftParams := r.current, r.params
+	r.current, r.params, r.shown = route, params, path
+	r.lock.Unlock()
+
+	if left != nil {
+		for _, f := range left.leave {
+			f(leftParams)
+		}
+	}
+	if err := r.Elements.Clear(); err != nil {
+		return err
+	}
+	if build != nil {
+		if page := build(params); page != nil {
+			if err := r.Elements.Insert(0, page); err != nil {
+				return err
+			}
+		}
+	}
+	if route != nil {
+		for _, f := range route.enter {
+			f(params)
+		}
+	}
+	return nil
+}
+
+// cleanPath turns a location hash or path into a path starting with /
+func cleanPath(path string) string {
+	return "/" + strings.Join(splitPath(strings.TrimPrefix(path, "#")), "/")
+}
+
+// SetStyle sets a CSS property of the Router, or removes it if value is empty
+func (r *Router) SetStyle(property, value string) error {
+	return r.live.setStyle(&r.StyleName, property, value)
+}
+
+// Show displays the Router
+func (r *Router) Show() error { return r.live.show(&r.StyleName) }
+
+// Hide hides the Router
+func (r *Router) Hide() error { return r.live.hide(&r.StyleName) }
+
+// boundHandlers binds the function told when the path changes, along with those registered with On
+func (r *Router) boundHandlers() map[string]interface{} {
+	handlers := r.Events.boundHandlers()
+	handlers[r.function()] = r.changed
+	return handlers
+}
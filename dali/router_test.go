@@ -0,0 +1,58 @@
+package dali
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestRouterStringHasNoSideEffects(t *testing.T) {
+	entered := 0
+	r := NewRouter("pages")
+	r.Handle("/", func(Params) Element { return Text("home") }).OnEnter(func(Params) { entered++ })
+
+	if html := fmt.Sprintf("%s", r); strings.Contains(html, "home") || entered != 0 || r.Path() != "" {
+		t.Errorf(`expected an empty Router but got "%s" after %d enters`, html, entered)
+	}
+
+	if err := start(r); err != nil {
+		t.Fatal(err)
+	}
+	if html := fmt.Sprintf("%s", r); !strings.Contains(html, "home") || entered != 1 || r.Path() != "/" {
+		t.Errorf(`expected "%s" but got "%s" after %d enters`, "home", html, entered)
+	}
+	if err := start(r); err != nil || entered != 1 {
+		t.Errorf("expected the Home page to be shown once but it was entered %d times", entered)
+	}
+}
+
+func TestRouterNavigateKeepsHistory(t *testing.T) {
+	r := NewRouter("pages")
+	for _, path := range []string{"/", "/a", "/b"} {
+		path := path
+		r.Handle(path, func(Params) Element { return Text(path) })
+	}
+	startedWindow(t, &fakeUI{}, r)
+
+	// the page reports each path Go sends it to, as its hashchange does
+	for _, path := range []string{"/a", "/b", "/a"} {
+		if err := r.Navigate(path); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.changed("#" + path); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected := "[/ /a /b /a]"
+	if history := fmt.Sprint(r.history); history != expected || r.Path() != "/a" || !r.CanGoBack() || r.CanGoForward() {
+		t.Errorf(`expected "%s" at /a but got "%s" at %s`, expected, history, r.Path())
+	}
+
+	// the browser's back button is told apart from a link by the history
+	if err := r.changed("#/b"); err != nil {
+		t.Fatal(err)
+	}
+	if history := fmt.Sprint(r.history); history != expected || r.Path() != "/b" || !r.CanGoForward() {
+		t.Errorf(`expected "%s" at /b but got "%s" at %s`, expected, history, r.Path())
+	}
+}
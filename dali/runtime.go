@@ -0,0 +1,188 @@
+package dali
+
+// runtimeScript is rendered in the head of the page of a Window, so it runs
+// before anything in the page needs it and again whenever the page reloads.
+// It holds the small helpers that rendered elements call to hand events over to Go.
+const runtimeScript = `
+window.dali = window.dali || {};
+(function(dali){
+	// calls to a Go function are numbered one by one, so Go can apply them in order;
+	// the page is told apart from the one before a reload by when it loaded
+	dali.page = Date.now();
+	dali.counts = {};
+	dali.send = function(name, message){
+		if (typeof window[name] !== "function") { return; }
+		message.page = dali.page;
+		message.seq = dali.counts[name] = (dali.counts[name] || 0) + 1;
+		return window[name](message);
+	};
+	// drawing on a canvas waits for the images it draws to load and for the
+	// drawing before it, so the canvas is drawn in order; a failed load is an error
+	dali.images = {};
+	dali.draw = function(id, urls, draw){
+		var el = document.getElementById(id);
+		if (!el) { throw new Error("no canvas " + id); }
+		var ctx = el.getContext("2d");
+		var loading = urls.filter(function(u){ return !(dali.images[u] && dali.images[u].complete); });
+		if (!loading.length && !el.daliDrawing) { draw(ctx); return; }
+		var loads = urls.map(function(u){
+			var img = dali.images[u];
+			if (!img) {
+				img = dali.images[u] = new Image();
+				img.src = u;
+			}
+			return img.decode().catch(function(e){
+				delete dali.images[u];
+				throw new Error("cannot load image " + u);
+			});
+		});
+		var run = Promise.all([el.daliDrawing].concat(loads)).then(function(){ draw(ctx); });
+		var queued = el.daliDrawing = run.catch(function(){}).then(function(){
+			if (el.daliDrawing === queued) { el.daliDrawing = null; }
+		});
+		return run;
+	};
+	dali.fragment = function(html){
+		var t = document.createElement("template");
+		t.innerHTML = html;
+		return t.content;
+	};
+	dali.node = function(path){
+		var n = document.documentElement;
+		for (var i = 0; i < path.length; i++) { n = n.childNodes[path[i]]; }
+		return n;
+	};
+	dali.patch = function(patches){
+		patches.forEach(function(p){
+			var n = dali.node(p.path);
+			switch (p.op) {
+			case "replace": n.replaceWith(dali.fragment(p.html || "")); break;
+			case "insert": n.insertBefore(dali.fragment(p.html || ""), n.childNodes[p.index || 0] || null); break;
+			case "move": n.insertBefore(n.childNodes[p.from || 0], n.childNodes[p.index || 0]); break;
+			case "remove": n.remove(); break;
+			case "text": n.textContent = p.value || ""; break;
+			case "attr":
+				n.setAttribute(p.name, p.value || "");
+				if (p.name === "value") { n.value = p.value || ""; }
+				if (p.name === "checked" || p.name === "selected") { n[p.name] = true; }
+				break;
+			case "unattr":
+				n.removeAttribute(p.name);
+				if (p.name === "checked" || p.name === "selected") { n[p.name] = false; }
+				break;
+			}
+		});
+	};
+	// the children of an Elements each start with a marker comment, dali:key,
+	// and run to the next marker, the last one being /dali
+	dali.marked = function(p, key){
+		var nodes = [], inside = false;
+		for (var n = p ? p.firstChild : null; n; n = n.nextSibling) {
+			var marker = n.nodeType === 8 && /^(dali:|\/dali$)/.test(n.data);
+			if (inside && marker) { break; }
+			if (marker && n.data === key) { inside = true; }
+			if (inside) { nodes.push(n); }
+		}
+		return nodes;
+	};
+	dali.insert = function(p, before, html){
+		var at = dali.marked(p, before || "/dali")[0];
+		if (!at) { return false; }
+		p.insertBefore(dali.fragment(html), at);
+		return true;
+	};
+	dali.remove = function(p, key){
+		var nodes = dali.marked(p, key);
+		nodes.forEach(function(n){ n.remove(); });
+		return nodes.length > 0;
+	};
+	dali.replace = function(p, key, html){
+		var nodes = dali.marked(p, key);
+		if (!nodes.length) { return false; }
+		p.insertBefore(dali.fragment(html), nodes[0]);
+		nodes.forEach(function(n){ n.remove(); });
+		return true;
+	};
+	dali.move = function(p, key, before){
+		var nodes = dali.marked(p, key), at = dali.marked(p, before || "/dali")[0];
+		if (!nodes.length || !at) { return false; }
+		nodes.forEach(function(n){ p.insertBefore(n, at); });
+		return true;
+	};
+	dali.clear = function(p){
+		var end = dali.marked(p, "/dali")[0], n = p ? p.firstChild : null;
+		if (!end) { return false; }
+		while (n && n !== end && !(n.nodeType === 8 && /^dali:/.test(n.data))) { n = n.nextSibling; }
+		while (n && n !== end) { var next = n.nextSibling; n.remove(); n = next; }
+		return true;
+	};
+	dali.event = function(e){
+		if (e.type === "submit") { e.preventDefault(); }
+		var t = e.target || {};
+		return {
+			type: e.type,
+			target: t.id || "",
+			value: t.value === undefined || t.value === null ? "" : String(t.value),
+			checked: !!t.checked,
+			key: e.key || "",
+			x: e.offsetX || 0,
+			y: e.offsetY || 0,
+			button: e.button || 0,
+			alt: !!e.altKey, ctrl: !!e.ctrlKey, shift: !!e.shiftKey, meta: !!e.metaKey
+		};
+	};
+	dali.form = function(e){
+		e.preventDefault();
+		var values = {};
+		new FormData(e.target).forEach(function(v, k){
+			(values[k] = values[k] || []).push(typeof v === "string" ? v : v.name);
+		});
+		return values;
+	};
+	dali.selected = function(el){
+		return Array.prototype.filter.call(el.options, function(o){ return o.selected; })
+			.map(function(o){ return o.value; });
+	};
+	// external links open in the system browser rather than replacing the application
+	document.addEventListener("click", function(e){
+		var a = e.target && e.target.closest ? e.target.closest("a[href],area[href]") : null;
+		if (!a || e.defaultPrevented || !window.dali_open) { return; }
+		var href = a.getAttribute("href");
+		if (/^(https?|mailto):/i.test(href)) {
+			e.preventDefault();
+			window.dali_open(a.href);
+		}
+	});
+	// routers are told the path after the # whenever it changes
+	window.addEventListener("hashchange", function(){
+		Array.prototype.forEach.call(document.querySelectorAll("[data-dali-router]"), function(r){
+			var f = window[r.getAttribute("data-dali-router")];
+			if (typeof f === "function") { f(location.hash); }
+		});
+	});
+	// Go is told when the system switches between light and dark color schemes
+	if (window.matchMedia) {
+		var scheme = window.matchMedia("(prefers-color-scheme: dark)");
+		var changed = function(e){
+			if (window.dali_color_scheme) { window.dali_color_scheme(e.matches); }
+		};
+		if (scheme.addEventListener) { scheme.addEventListener("change", changed); } else { scheme.addListener(changed); }
+	}
+	dali.pointer = function(e, el){
+		if (e.type === "pointerdown") { el.setPointerCapture(e.pointerId); }
+		var r = el.getBoundingClientRect();
+		return {
+			type: e.type,
+			x: (e.clientX - r.left - el.clientLeft) * el.width / el.clientWidth,
+			y: (e.clientY - r.top - el.clientTop) * el.height / el.clientHeight,
+			pointerId: e.pointerId || 0,
+			pointerType: e.pointerType || "mouse",
+			button: e.button,
+			buttons: e.buttons,
+			deltaX: e.deltaX || 0,
+			deltaY: e.deltaY || 0,
+			alt: e.altKey, ctrl: e.ctrlKey, shift: e.shiftKey, meta: e.metaKey
+		};
+	};
+})(window.dali);
+`
@@ -0,0 +1,323 @@
+package dali
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Option is one choice of a Select
+type Option struct {
+	Label    string
+	Value    string
+	Selected bool
+	Disabled bool
+}
+
+// String for Option
+func (o Option) String() string {
+	attrs := attribute("value", o.Value)
+	if o.Selected {
+		attrs += ` selected`
+	}
+	if o.Disabled {
+		attrs += ` disabled`
+	}
+	return fmt.Sprintf(`<option%s>%s</option>`, attrs, escape(o.Label))
+}
+
+// OptionsOf makes Options whose labels are their values
+func OptionsOf(values ...string) []Option {
+	options := make([]Option, len(values))
+	for i, v := range values {
+		options[i] = Option{Label: v, Value: v}
+	}
+	return options
+}
+
+// OptGroup is a labelled group of the Options of a Select
+type OptGroup struct {
+	Label    string
+	Options  []Option
+	Disabled bool
+}
+
+// String for OptGroup
+func (g OptGroup) String() string {
+	disabled := ""
+	if g.Disabled {
+		disabled = ` disabled`
+	}
+	html := ""
+	for _, o := range g.Options {
+		html += o.String()
+	}
+	return fmt.Sprintf(`<optgroup%s%s>%s</optgroup>`, attribute("label", g.Label), disabled, html)
+}
+
+// Select is a drop-down list, or a list box when Multiple.  The selection is
+// kept in step with the page, so it can be read from Go at any time.
+type Select struct {
+	ID        string
+	Field     string // the name the selection is submitted under
+	Multiple  bool
+	Size      int
+	StyleName string
+	Required  bool
+	Disabled  bool
+	options   []Option
+	groups    []OptGroup
+	sync      string
+	onChange  []func(selected []string)
+	order     sequencer
+	lock      sync.Mutex
+	validity
+	BaseElement
+}
+
+// NewSelect creates a Select offering options, with name as both its ID and its Field
+func NewSelect(name string, options []Option) *Select {
+	return &Select{
+		ID:      name,
+		Field:   name,
+		options: append([]Option{}, options...),
+		sync:    functionName("select"),
+	}
+}
+
+// String for Select
+func (s *Select) String() string {
+	attrs := attribute("id", s.ID)
+	if s.Field != "" {
+		attrs += attribute("name", s.Field)
+	}
+	if s.Multiple {
+		attrs += ` multiple`
+	}
+	if s.Size > 0 {
+		attrs += attribute("size", fmt.Sprint(s.Size))
+	}
+	if s.StyleName != "" {
+		attrs += attribute("style", s.StyleName)
+	}
+	if s.Required {
+		attrs += ` required`
+	}
+	if s.Disabled {
+		attrs += ` disabled`
+	}
+	onchange := fmt.Sprintf("dali.send(%s,{values:dali.selected(this)})", jsString(s.function()))
+	if handler := s.Events.script("change"); handler != "" {
+		onchange = fmt.Sprintf("%s;%s", onchange, handler)
+	}
+	attrs += attribute("onchange", onchange)
+	return fmt.Sprintf(`<select%s%s%s%s>%s</select>%s`, attrs, s.invalidAttribute(), s.attributes(), s.Events.attributes("change"), s.optionsHTML(), s.errorHTML(s.ID))
+}
+
+// optionsHTML renders the options followed by the groups
+func (s *Select) optionsHTML() string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	html := ""
+	for _, o := range s.options {
+		html += o.String()
+	}
+	for _, g := range s.groups {
+		html += g.String()
+	}
+	return html
+}
+
+// function is the name of the JavaScript function keeping the selection in step
+func (s *Select) function() string {
+	if s.sync == "" {
+		s.sync = functionName("select")
+	}
+	return s.sync
+}
+
+// selectMessage is the selection as the page sends it, numbered in the order the changes happened
+type selectMessage struct {
+	Values []string `json:"values"`
+	sequenced
+}
+
+// Name of the Select
+func (s *Select) Name() string { return s.ID }
+
+// field is the name the Select is submitted under
+func (s *Select) field() string { return s.Field }
+
+// Style of the Select
+func (s *Select) Style() string { return s.StyleName }
+
+// Styles of the Select
+func (s *Select) Styles() Styles { return parseStyles(s.StyleName) }
+
+// AddGroup adds a labelled group of options after the existing ones
+func (s *Select) AddGroup(label string, options []Option) error {
+	s.lock.Lock()
+	group := OptGroup{Label: label, Options: append([]Option{}, options...)}
+	s.groups = append(s.groups, group)
+	s.lock.Unlock()
+	html := group.String()
+	return s.live.change(func(t *tree, n *vnode) { t.splice(n, len(n.children), 0, parseHTML(html)...) },
+		`el.insertAdjacentHTML("beforeend",%s);`, html)
+}
+
+// SetOptions replaces every option and group of the Select
+func (s *Select) SetOptions(options []Option) error {
+	s.lock.Lock()
+	s.options = append([]Option{}, options...)
+	s.groups = nil
+	s.lock.Unlock()
+	return s.live.setHTML(s.optionsHTML())
+}
+
+// Selected returns the values of the selected options.  A drop-down with none
+// marked Selected has its first enabled option selected, as the page shows it.
+func (s *Select) Selected() []string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	selected := []string{}
+	s.eachOption(func(o *Option) {
+		if o.Selected {
+			selected = append(selected, o.Value)
+		}
+	})
+	if len(selected) > 0 || s.Multiple || s.Size > 1 {
+		return selected
+	}
+	for _, o := range s.options {
+		if !o.Disabled {
+			return []string{o.Value}
+		}
+	}
+	for _, g := range s.groups {
+		for _, o := range g.Options {
+			if !g.Disabled && !o.Disabled {
+				return []string{o.Value}
+			}
+		}
+	}
+	return selected
+}
+
+// Value returns the value of the first selected option
+func (s *Select) Value() string {
+	if selected := s.Selected(); len(selected) > 0 {
+		return selected[0]
+	}
+	return ""
+}
+
+// Select selects the options with the given values and no others
+func (s *Select) Select(values ...string) error {
+	s.choose(values)
+	chosen := map[string]bool{}
+	for _, v := range values {
+		chosen[v] = true
+	}
+	return s.live.change(func(t *tree, n *vnode) {
+		n.each(func(o *vnode) {
+			if o.tag == "option" && chosen[o.attrs["value"]] {
+				o.setAttr("selected", "")
+			} else if o.tag == "option" {
+				o.removeAttr("selected")
+			}
+		})
+	}, `var v=%s;Array.prototype.forEach.call(el.options,function(o){o.selected=v.indexOf(o.value)>=0;});`, values)
+}
+
+// choose marks the options with the given values as the selected ones
+func (s *Select) choose(values []string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.eachOption(func(o *Option) {
+		o.Selected = false
+		for _, v := range values {
+			if o.Value == v {
+				o.Selected = true
+			}
+		}
+	})
+}
+
+// eachOption calls f with every option, grouped or not
+func (s *Select) eachOption(f func(o *Option)) {
+	for i := range s.options {
+		f(&s.options[i])
+	}
+	for g := range s.groups {
+		for i := range s.groups[g].Options {
+			f(&s.groups[g].Options[i])
+		}
+	}
+}
+
+// OnChange calls f with the selected values whenever the user changes the selection
+func (s *Select) OnChange(f func(selected []string)) {
+	s.onChange = append(s.onChange, f)
+}
+
+// SetStyle sets a CSS property of the Select, or removes it if value is empty
+func (s *Select) SetStyle(property, value string) error {
+	return s.live.setStyle(&s.StyleName, property, value)
+}
+
+// Show displays the Select
+func (s *Select) Show() error { return s.live.show(&s.StyleName) }
+
+// Hide hides the Select
+func (s *Select) Hide() error { return s.live.hide(&s.StyleName) }
+
+// boundHandlers binds the function keeping the selection in step, along with those registered with On
+func (s *Select) boundHandlers() map[string]interface{} {
+	handlers := s.Events.boundHandlers()
+	handlers[s.function()] = func(m selectMessage) {
+		s.order.apply(m.sequenced, func() {
+			s.choose(m.Values)
+			for _, f := range s.onChange {
+				f(m.Values)
+			}
+		})
+	}
+	return handlers
+}
+
+// DataList offers suggestions to an Input whose List is the ID of the DataList
+type DataList struct {
+	ID      string
+	Options []Option
+	BaseElement
+}
+
+// NewDataList creates a DataList suggesting values
+func NewDataList(name string, values ...string) *DataList {
+	return &DataList{ID: name, Options: OptionsOf(values...)}
+}
+
+// String for DataList
+func (d *DataList) String() string {
+	options := []string{}
+	for _, o := range d.Options {
+		options = append(options, o.String())
+	}
+	return fmt.Sprintf(`<datalist%s%s>%s</datalist>`, attribute("id", d.ID), d.attributes(), strings.Join(options, ""))
+}
+
+// Name of the DataList
+func (d *DataList) Name() string { return d.ID }
+
+// SetOptions replaces the suggestions of the DataList
+func (d *DataList) SetOptions(options []Option) error {
+	d.Options = append([]Option{}, options...)
+	html := ""
+	for _, o := range d.Options {
+		html += o.String()
+	}
+	return d.live.setHTML(html)
+}
+
+// showInvalid shows a validation error after the Select
+func (s *Select) showInvalid(message string) error { return s.validity.show(&s.live, s.ID, message) }
@@ -0,0 +1,32 @@
+package dali
+
+import "testing"
+
+func TestSelectFallsBackToFirstEnabledOption(t *testing.T) {
+	options := OptionsOf("a", "b", "c")
+	options[0].Disabled = true
+	s := NewSelect("letters", options)
+	if value := s.Value(); value != "b" {
+		t.Errorf(`expected "%s" but got "%s"`, "b", value)
+	}
+
+	s.Multiple = true
+	if selected := s.Selected(); len(selected) != 0 {
+		t.Errorf(`expected nothing selected but got %v`, selected)
+	}
+}
+
+func TestSelectAppliesChangesInOrder(t *testing.T) {
+	s := NewSelect("letters", OptionsOf("a", "b", "c"))
+	changed := []string{}
+	s.OnChange(func(selected []string) { changed = append(changed, selected...) })
+	update := s.boundHandlers()[s.function()].(func(selectMessage))
+	update(selectMessage{[]string{"c"}, sequenced{Page: 1, Seq: 2}})
+	update(selectMessage{[]string{"b"}, sequenced{Page: 1, Seq: 1}})
+	if value := s.Value(); value != "c" {
+		t.Errorf(`expected "%s" but got "%s"`, "c", value)
+	}
+	if len(changed) != 2 || changed[0] != "b" || changed[1] != "c" {
+		t.Errorf(`expected changes [b c] but got %v`, changed)
+	}
+}
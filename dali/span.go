@@ -0,0 +1,59 @@
+package dali
+
+import "fmt"
+
+//Span element
+type Span struct {
+	ID        string
+	Text      string
+	StyleName string
+	markup    bool
+	BaseElement
+}
+
+//String for span
+func (s *Span) String() string {
+	style := ""
+	if s.StyleName != "" {
+		style = attribute("style", s.StyleName)
+	}
+	text := escape(s.Text)
+	if s.markup {
+		text = s.Text
+	}
+	return fmt.Sprintf(`<span id="%s"%s%s%s>%s</span>`, escape(s.ID), style, s.attributes(), s.Events.attributes(), text)
+}
+
+//Style for span
+func (s *Span) Style() string { return s.StyleName }
+
+//Styles for span
+func (s *Span) Styles() Styles { return parseStyles(s.StyleName) }
+
+//Name returns the name of the Span
+func (s *Span) Name() string { return s.ID }
+
+// SetText replaces the text of the Span
+func (s *Span) SetText(text string) error {
+	s.Text = text
+	s.markup = false
+	return s.live.setText(text)
+}
+
+// SetHTML replaces the contents of the Span with markup
+func (s *Span) SetHTML(html string) error {
+	s.Text = html
+	s.markup = true
+	return s.live.setHTML(html)
+}
+
+// SetStyle sets a CSS property of the Span, or removes it if value is empty
+func (s *Span) SetStyle(property, value string) error {
+	return s.live.setStyle(&s.StyleName, property, value)
+}
+
+// Show displays the Span
+func (s *Span) Show() error { return s.live.show(&s.StyleName) }
+
+// Hide hides the Span
+func (s *Span) Hide() error { return s.live.hide(&s.StyleName) }
@@ -0,0 +1,174 @@
+package dali
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// TextSetter is an element whose text can be changed, such as a Div, Span or Header
+type TextSetter interface {
+	SetText(text string) error
+}
+
+// AttrSetter is an element whose attributes can be changed
+type AttrSetter interface {
+	SetAttr(name, value string) error
+}
+
+// StyleSetter is an element whose style can be changed
+type StyleSetter interface {
+	SetStyle(property, value string) error
+}
+
+// Shower is an element that can be shown and hidden
+type Shower interface {
+	Show() error
+	Hide() error
+}
+
+// State is an observable value - a string, number, bool or any other value that
+// encodes as JSON.  Elements bound to a State are brought up to date whenever it
+// is Set, which is safe from any goroutine.
+type State struct {
+	value    interface{}
+	bindings []func(interface{}) error
+	lock     sync.Mutex
+	updating sync.Mutex
+}
+
+// NewState creates a State holding value
+func NewState(value interface{}) *State {
+	return &State{value: value}
+}
+
+// Get returns the value of the State
+func (s *State) Get() interface{} {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.value
+}
+
+// String is the value as it is shown in text
+func (s *State) String() string { return display(s.Get()) }
+
+// Set changes the value and updates every bound element, returning the first error
+func (s *State) Set(value interface{}) error {
+	return s.Update(func(interface{}) interface{} { return value })
+}
+
+// Update sets the value to the result of f, which is passed the current value.
+// No other Set or Update can run in between, so Update is safe for counters.
+func (s *State) Update(f func(value interface{}) interface{}) error {
+	s.updating.Lock()
+	defer s.updating.Unlock()
+
+	s.lock.Lock()
+	old := s.value
+	s.value = f(old)
+	value := s.value
+	bindings := append([]func(interface{}) error{}, s.bindings...)
+	s.lock.Unlock()
+
+	if reflect.DeepEqual(old, value) {
+		return nil
+	}
+	var first error
+	for _, apply := range bindings {
+		if err := apply(value); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// Observe calls f with the value now and every time it changes.  f must not
+// Set or Update the State itself.
+func (s *State) Observe(f func(value interface{})) error {
+	return s.bind(func(v interface{}) error { f(v); return nil })
+}
+
+// bind applies the current value with apply, then keeps applying every new one
+func (s *State) bind(apply func(interface{}) error) error {
+	s.updating.Lock()
+	defer s.updating.Unlock()
+
+	s.lock.Lock()
+	s.bindings = append(s.bindings, apply)
+	value := s.value
+	s.lock.Unlock()
+
+	return apply(value)
+}
+
+// BindText shows the value as the text of el
+func (s *State) BindText(el TextSetter) error {
+	return s.bind(func(v interface{}) error { return el.SetText(display(v)) })
+}
+
+// BindTextf shows the value as the text of el, formatted as by fmt.Sprintf
+func (s *State) BindTextf(el TextSetter, format string) error {
+	return s.bind(func(v interface{}) error { return el.SetText(fmt.Sprintf(format, v)) })
+}
+
+// BindAttr keeps the value in the named attribute of el
+func (s *State) BindAttr(el AttrSetter, name string) error {
+	return s.bind(func(v interface{}) error { return el.SetAttr(name, display(v)) })
+}
+
+// BindStyle keeps the value in a CSS property of el
+func (s *State) BindStyle(el StyleSetter, property string) error {
+	return s.bind(func(v interface{}) error { return el.SetStyle(property, display(v)) })
+}
+
+// BindVisible shows el while the value is truthy - anything but nil, false,
+// zero, an empty string or an empty slice or map - and hides it otherwise
+func (s *State) BindVisible(el Shower) error {
+	return s.bind(func(v interface{}) error {
+		if truthy(v) {
+			return el.Show()
+		}
+		return el.Hide()
+	})
+}
+
+// display renders a value as text: strings as they are, other values as JSON
+func display(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case fmt.Stringer:
+		return t.String()
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+// truthy follows JavaScript: nil, false, zero and empty values are false
+func truthy(v interface{}) bool {
+	if v == nil {
+		return false
+	}
+	r := reflect.ValueOf(v)
+	switch r.Kind() {
+	case reflect.Bool:
+		return r.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return r.Int() != 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return r.Uint() != 0
+	case reflect.Float32, reflect.Float64:
+		return r.Float() != 0
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return r.Len() > 0
+	case reflect.Ptr, reflect.Interface:
+		return !r.IsNil()
+	}
+	return true
+}
@@ -0,0 +1,67 @@
+package dali
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestStateUpdatesBoundElements(t *testing.T) {
+	count := NewState(0)
+	label := &Span{ID: "count"}
+	badge := &Span{ID: "badge"}
+	if err := count.BindTextf(label, "%d clicks"); err != nil {
+		t.Fatal(err)
+	}
+	if err := count.BindVisible(badge); err != nil {
+		t.Fatal(err)
+	}
+	if err := count.BindAttr(badge, "data-count"); err != nil {
+		t.Fatal(err)
+	}
+	if label.Text != "0 clicks" || !strings.Contains(badge.StyleName, "display:none") {
+		t.Errorf(`expected "0 clicks" and a hidden badge but got "%s" and "%s"`, label.Text, badge.StyleName)
+	}
+
+	ui := &fakeUI{}
+	w := startedWindow(t, ui, label, badge)
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			count.Update(func(v interface{}) interface{} { return v.(int) + 1 })
+		}()
+	}
+	wg.Wait()
+	if label.Text != "20 clicks" || badge.Attr("data-count") != "20" || strings.Contains(badge.StyleName, "display:none") {
+		t.Errorf(`expected "20 clicks" on a shown badge but got "%s", "%s" and "%s"`, label.Text, badge.Attr("data-count"), badge.StyleName)
+	}
+	inStep(t, w, "counting")
+
+	evals := len(ui.evals)
+	if err := count.Set(20); err != nil || len(ui.evals) != evals {
+		t.Errorf("expected setting the same value to change nothing but it ran %v", ui.evals[evals:])
+	}
+}
+
+func TestStateDisplayAndTruthy(t *testing.T) {
+	for v, expected := range map[interface{}]string{nil: "", "a": "a", 1.5: "1.5", true: "true"} {
+		if s := display(v); s != expected {
+			t.Errorf(`expected "%s" but got "%s"`, expected, s)
+		}
+	}
+	if s := display([]int{1, 2}); s != "[1,2]" {
+		t.Errorf(`expected "%s" but got "%s"`, "[1,2]", s)
+	}
+	for _, v := range []interface{}{nil, false, 0, 0.0, "", []int{}, map[string]int{}} {
+		if truthy(v) {
+			t.Errorf("expected %#v to be false", v)
+		}
+	}
+	for _, v := range []interface{}{true, -1, "0", []int{0}, struct{}{}} {
+		if !truthy(v) {
+			t.Errorf("expected %#v to be true", v)
+		}
+	}
+}
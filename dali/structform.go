@@ -0,0 +1,336 @@
+package dali
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Validator is a struct given to FormFor that checks itself when the form is
+// submitted.  Returning ValidationErrors shows each next to its field.
+type Validator interface {
+	Validate() error
+}
+
+// fieldSpec is what the dali tag of a struct field asks of its form field
+type fieldSpec struct {
+	index    int
+	name     string
+	label    string
+	widget   string
+	min      string
+	max      string
+	options  []string
+	required bool
+}
+
+// parseFieldSpec reads the dali tag of sf, such as `dali:"label=Port,min=1,required"`
+func parseFieldSpec(index int, sf reflect.StructField) (fieldSpec, error) {
+	spec := fieldSpec{index: index, name: formField(sf), label: sf.Name}
+	for _, part := range strings.Split(sf.Tag.Get("dali"), ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		value := ""
+		if len(kv) == 2 {
+			value = kv[1]
+		}
+		switch kv[0] {
+		case "":
+		case "label":
+			spec.label = value
+		case "widget":
+			spec.widget = value
+		case "min":
+			spec.min = value
+		case "max":
+			spec.max = value
+		case "options":
+			spec.options = strings.Split(value, "|")
+		case "required":
+			spec.required = true
+		default:
+			return spec, fmt.Errorf("field %s: unknown dali tag option %q", sf.Name, kv[0])
+		}
+	}
+	if spec.widget == "" {
+		spec.widget = defaultWidget(sf.Type, spec.options)
+	}
+	return spec, nil
+}
+
+// defaultWidget chooses the widget for a field of type t
+func defaultWidget(t reflect.Type, options []string) string {
+	kind := valueKind(t)
+	switch {
+	case len(options) > 0:
+		return "select"
+	case kind == reflect.Bool:
+		return string(CheckboxInput)
+	case kind >= reflect.Int && kind <= reflect.Float64:
+		return string(NumberInput)
+	}
+	return string(TextInput)
+}
+
+// editable is true for the types a form field can edit: strings, bools and
+// numbers, pointers to them, and slices of them for a multiple select
+func editable(t reflect.Type) bool {
+	if t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Bool, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+// valueKind is the kind of the values of type t, looking through a pointer
+func valueKind(t reflect.Type) reflect.Kind {
+	if t.Kind() == reflect.Ptr {
+		return t.Elem().Kind()
+	}
+	return t.Kind()
+}
+
+// fieldText is a value as its widget shows it, a nil pointer showing as empty
+func fieldText(v reflect.Value) string {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// structForm keeps a struct in step with the form generated from it
+type structForm struct {
+	form   *Form
+	target reflect.Value
+	specs  []fieldSpec
+	errors ValidationErrors
+	lock   sync.Mutex
+}
+
+// FormFor generates a Form editing the struct pointed to by target.  Each
+// exported field gets a label and a widget chosen by its type, or by the
+// options of its dali tag:
+//
+//	label=Text           the caption of the field, its name by default
+//	widget=number        text, number, password, email, checkbox, range, color, date or select
+//	min=1,max=10         the range of a number
+//	options=a|b|c        the choices of a select, a multiple select for a []string
+//	required             the field must not be left empty
+//
+// Strings, bools and numbers can be edited, as can pointers to them, a nil
+// pointer being an empty field, and slices of them with a multiple select.
+// Fields of other types, such as structs, maps and time.Time, are left out,
+// as is a field tagged "-".  Every change the user makes is checked
+// and written back into the struct, from the goroutine delivering the event;
+// a change that does not pass is shown next to its field instead.  Submitting
+// the Form checks every field, then calls Validate if the struct is a Validator.
+func FormFor(name string, target interface{}) (*Form, error) {
+	r := reflect.ValueOf(target)
+	if r.Kind() != reflect.Ptr || r.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("FormFor needs a pointer to a struct, not %T", target)
+	}
+	s := &structForm{form: NewForm(name), target: r.Elem(), errors: ValidationErrors{}}
+	t := s.target.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.Tag.Get("dali") == "-" || formField(sf) == "" || !editable(sf.Type) {
+			continue
+		}
+		spec, err := parseFieldSpec(i, sf)
+		if err != nil {
+			return nil, err
+		}
+		widget, err := s.widget(spec)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %v", sf.Name, err)
+		}
+		row := NewDiv(fmt.Sprintf("%s-%s-field", name, spec.name))
+		row.AddClass("dali-field")
+		row.Elements.AddElement(NewLabel("", widget.Name(), spec.label))
+		row.Elements.AddElement(widget)
+		s.form.Elements.AddElement(row)
+		s.specs = append(s.specs, spec)
+	}
+	save := &Button{ID: name + "-save", ButtonText: "Save"}
+	s.form.Elements.AddElement(save)
+	s.form.OnSubmit(s.submitted)
+	return s.form, nil
+}
+
+// widget creates the element editing the field described by spec
+func (s *structForm) widget(spec fieldSpec) (Element, error) {
+	id := fmt.Sprintf("%s-%s", s.form.ID, spec.name)
+	field := s.target.Field(spec.index)
+	if spec.widget == "select" {
+		selected := map[string]bool{}
+		if field.Kind() == reflect.Slice {
+			for i := 0; i < field.Len(); i++ {
+				selected[fieldText(field.Index(i))] = true
+			}
+		} else {
+			selected[fieldText(field)] = true
+		}
+		options := OptionsOf(spec.options...)
+		for i := range options {
+			options[i].Selected = selected[options[i].Value]
+		}
+		sel := NewSelect(id, options)
+		sel.Field = spec.name
+		sel.Multiple = field.Kind() == reflect.Slice
+		sel.Required = spec.required
+		sel.OnChange(func(values []string) { s.edited(spec, values) })
+		return sel, nil
+	}
+
+	switch InputType(spec.widget) {
+	case TextInput, NumberInput, PasswordInput, EmailInput, CheckboxInput, RangeInput, ColorInput, DateInput:
+	default:
+		return nil, fmt.Errorf("unknown widget %q", spec.widget)
+	}
+	if field.Kind() == reflect.Slice {
+		return nil, fmt.Errorf("a %s cannot be edited with a %s widget", field.Type(), spec.widget)
+	}
+	input := NewInput(InputType(spec.widget), id, "")
+	input.Field = spec.name
+	input.Min, input.Max = spec.min, spec.max
+	input.Required = spec.required
+	if input.Type == CheckboxInput {
+		input.value = "true"
+		input.checked = fieldText(field) == "true"
+		input.OnChange(func(value string) { s.edited(spec, []string{value}) })
+		return input, nil
+	}
+	if kind := valueKind(field.Type()); kind == reflect.Float32 || kind == reflect.Float64 {
+		input.Step = "any"
+	}
+	input.value = fieldText(field)
+	input.OnChange(func(value string) { s.edited(spec, []string{value}) })
+	return input, nil
+}
+
+// edited writes a change made in the page into the struct, showing whether it passed
+func (s *structForm) edited(spec fieldSpec, values []string) {
+	message := s.set(spec, values)
+	s.lock.Lock()
+	if s.errors[spec.name] == message {
+		s.lock.Unlock()
+		return
+	}
+	if message == "" {
+		delete(s.errors, spec.name)
+	} else {
+		s.errors[spec.name] = message
+	}
+	errs := s.copyErrors()
+	s.lock.Unlock()
+	s.form.SetErrors(errs)
+}
+
+// set checks values and writes them into the field, returning what is wrong if they do not pass
+func (s *structForm) set(spec fieldSpec, values []string) string {
+	filled := []string{}
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			filled = append(filled, v)
+		}
+	}
+	field := s.target.Field(spec.index)
+	if spec.required && len(filled) == 0 {
+		return "Required"
+	}
+	switch {
+	case field.Kind() == reflect.Slice:
+		values = filled
+	case valueKind(field.Type()) == reflect.Bool && (len(values) == 0 || values[0] == ""):
+		values = []string{"false"}
+	case len(values) == 0:
+		values = []string{""}
+	default:
+		values = values[:1]
+	}
+
+	v := reflect.New(field.Type()).Elem()
+	if err := setField(v, values); err != nil {
+		return err.Error()
+	}
+	if spec.required && valueKind(v.Type()) == reflect.Bool && !reflect.Indirect(v).Bool() {
+		return "Required"
+	}
+	if message := inRange(reflect.Indirect(v), spec); message != "" {
+		return message
+	}
+	s.lock.Lock()
+	field.Set(v)
+	s.lock.Unlock()
+	return ""
+}
+
+// inRange checks a number against the min and max of spec
+func inRange(v reflect.Value, spec fieldSpec) string {
+	var n float64
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n = float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n = float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		n = v.Float()
+	default:
+		return ""
+	}
+	if min, err := strconv.ParseFloat(spec.min, 64); err == nil && n < min {
+		return fmt.Sprintf("Must be at least %s", spec.min)
+	}
+	if max, err := strconv.ParseFloat(spec.max, 64); err == nil && n > max {
+		return fmt.Sprintf("Must be at most %s", spec.max)
+	}
+	return ""
+}
+
+// submitted checks and writes back every field, then asks the struct to validate itself
+func (s *structForm) submitted(values FormValues) error {
+	errs := ValidationErrors{}
+	for _, spec := range s.specs {
+		if message := s.set(spec, values[spec.name]); message != "" {
+			errs[spec.name] = message
+		}
+	}
+	if v, ok := s.target.Addr().Interface().(Validator); ok && len(errs) == 0 {
+		err := v.Validate()
+		if more, ok := err.(ValidationErrors); ok {
+			for name, message := range more {
+				errs[name] = message
+			}
+		} else if err != nil {
+			return err
+		}
+	}
+	s.lock.Lock()
+	s.errors = errs
+	s.lock.Unlock()
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// copyErrors copies the errors being shown, which must be locked
+func (s *structForm) copyErrors() ValidationErrors {
+	errs := ValidationErrors{}
+	for name, message := range s.errors {
+		errs[name] = message
+	}
+	return errs
+}
@@ -0,0 +1,81 @@
+package dali
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type settings struct {
+	Port  int    `dali:"label=Port number,min=1,max=65535,required"`
+	Color string `dali:"options=red|green|blue"`
+	Debug bool
+	Bad   string `dali:"colour=red"`
+}
+
+func TestParseFieldSpec(t *testing.T) {
+	typ := reflect.TypeOf(settings{})
+	port, err := parseFieldSpec(0, typ.Field(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := fieldSpec{index: 0, name: "Port", label: "Port number", widget: "number", min: "1", max: "65535", required: true}
+	if !reflect.DeepEqual(port, expected) {
+		t.Errorf(`expected %+v but got %+v`, expected, port)
+	}
+
+	color, _ := parseFieldSpec(1, typ.Field(1))
+	if color.widget != "select" || !reflect.DeepEqual(color.options, []string{"red", "green", "blue"}) {
+		t.Errorf(`expected a select of red, green and blue but got %+v`, color)
+	}
+	debug, _ := parseFieldSpec(2, typ.Field(2))
+	if debug.widget != "checkbox" || debug.label != "Debug" {
+		t.Errorf(`expected a checkbox labelled Debug but got %+v`, debug)
+	}
+	if _, err := parseFieldSpec(3, typ.Field(3)); err == nil {
+		t.Errorf("expected an error for an unknown option")
+	}
+}
+
+type server struct {
+	Name    *string
+	Port    *int `dali:"min=1"`
+	Started time.Time
+	Labels  map[string]string
+	settings
+}
+
+func TestFormForEditsPointersAndSkipsStructs(t *testing.T) {
+	port := 8080
+	s := &server{Port: &port}
+	form, err := FormFor("server", s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inputs := map[string]*Input{}
+	for i := 0; i < form.Elements.Len(); i++ {
+		if row, ok := form.Elements.Get(i).(*Div); ok {
+			input := row.Elements.Get(1).(*Input)
+			inputs[input.Field] = input
+		}
+	}
+	if len(inputs) != 2 || inputs["Name"].Value() != "" || inputs["Port"].Value() != "8080" {
+		t.Fatalf(`expected Name "" and Port "8080" but got %v`, inputs)
+	}
+
+	change := func(input *Input, value string) {
+		input.update(eventMessage{Event: Event{Type: "change", Value: value}})
+	}
+	change(inputs["Name"], "db")
+	change(inputs["Port"], "5432")
+	if s.Name == nil || *s.Name != "db" {
+		t.Errorf(`expected "%s" but got %v`, "db", s.Name)
+	}
+	if port != 8080 || *s.Port != 5432 {
+		t.Errorf(`expected 5432 but got %d`, *s.Port)
+	}
+	change(inputs["Name"], "")
+	if s.Name != nil {
+		t.Errorf(`expected an emptied Name to be nil but got "%s"`, *s.Name)
+	}
+}
@@ -0,0 +1,174 @@
+package dali
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// StyleSheet is a stylesheet loaded from a URL, read from a local file, or
+// given as CSS text.  A Window renders its stylesheets into the document head
+// in the order they were added, before any CSS built with rules.
+type StyleSheet struct {
+	ID      string
+	URL     string // loaded by the page
+	File    string // read when the stylesheet is added or reloaded, as the page cannot load local files
+	Text    string // CSS rendered into the page
+	version int    // counts Reloads of the URL, so the page fetches it again
+	window  *Window
+}
+
+// StyleSheetURL creates a StyleSheet loaded from url
+func StyleSheetURL(url string) *StyleSheet {
+	return &StyleSheet{URL: url}
+}
+
+// StyleSheetFile creates a StyleSheet from the CSS in a local file
+func StyleSheetFile(path string) (*StyleSheet, error) {
+	s := &StyleSheet{File: path}
+	if err := s.read(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// StyleSheetText creates a StyleSheet from CSS text
+func StyleSheetText(css string) *StyleSheet {
+	return &StyleSheet{Text: css}
+}
+
+// String for StyleSheet
+func (style StyleSheet) String() string {
+	id := ""
+	if style.ID != "" {
+		id = attribute("id", style.ID)
+	}
+	if style.URL != "" {
+		href := style.URL
+		if style.version > 0 {
+			separator := "?"
+			if strings.Contains(href, "?") {
+				separator = "&"
+			}
+			href = fmt.Sprintf("%s%sdali-reload=%d", href, separator, style.version)
+		}
+		return fmt.Sprintf(`<link rel="stylesheet"%s%s>`, id, attribute("href", href))
+	}
+	if style.Text != "" {
+		// the CSS must not end the style element it is rendered in
+		return fmt.Sprintf(`<style%s>%s</style>`, id, strings.Replace(style.Text, "</", `<\/`, -1))
+	}
+	return ""
+}
+
+// windowStyleSheet is the stylesheet given to NewWindow: a local file if one
+// is at that path, as the page cannot load it, and otherwise a URL
+func windowStyleSheet(location string) StyleSheet {
+	if location == "" {
+		return StyleSheet{}
+	}
+	if !strings.Contains(location, "://") {
+		if s, err := StyleSheetFile(location); err == nil {
+			return *s
+		}
+	}
+	return StyleSheet{URL: location}
+}
+
+// read takes the CSS from the file of the StyleSheet
+func (style *StyleSheet) read() error {
+	b, err := ioutil.ReadFile(style.File)
+	if err != nil {
+		return err
+	}
+	style.Text = string(b)
+	return nil
+}
+
+// SetURL loads the StyleSheet from url instead
+func (style *StyleSheet) SetURL(url string) error {
+	style.URL, style.File, style.Text = url, "", ""
+	return style.refresh()
+}
+
+// SetText replaces the CSS of the StyleSheet
+func (style *StyleSheet) SetText(css string) error {
+	style.URL, style.File, style.Text = "", "", css
+	return style.refresh()
+}
+
+// Reload reads the file of the StyleSheet again, or has the page load its URL again
+func (style *StyleSheet) Reload() error {
+	if style.File != "" {
+		if err := style.read(); err != nil {
+			return err
+		}
+		return style.refresh()
+	}
+	if style.URL == "" {
+		return nil
+	}
+	// a changed href makes the page fetch the stylesheet again
+	style.version++
+	return style.refresh()
+}
+
+// refresh brings the page up to date with the StyleSheet
+func (style *StyleSheet) refresh() error {
+	if style.window == nil || style.window.started() == nil {
+		return nil
+	}
+	return style.window.Update()
+}
+
+// AddStyleSheet adds a stylesheet to the document head, after those already
+// added.  Once the Window is running it is applied straight away.
+func (w *Window) AddStyleSheet(style *StyleSheet) error {
+	for _, s := range w.stylesheets {
+		if s == style {
+			return nil
+		}
+	}
+	style.window = w
+	w.stylesheets = append(w.stylesheets, style)
+	if w.started() == nil {
+		return nil
+	}
+	return w.Update()
+}
+
+// RemoveStyleSheet takes a stylesheet out of the document head
+func (w *Window) RemoveStyleSheet(style *StyleSheet) error {
+	for i, s := range w.stylesheets {
+		if s == style {
+			w.stylesheets = append(w.stylesheets[:i], w.stylesheets[i+1:]...)
+			style.window = nil
+			if w.started() == nil {
+				return nil
+			}
+			return w.Update()
+		}
+	}
+	return nil
+}
+
+// ReplaceStyleSheet puts style in the place of old, without reloading the page
+func (w *Window) ReplaceStyleSheet(old, style *StyleSheet) error {
+	for i, s := range w.stylesheets {
+		if s == old {
+			old.window = nil
+			style.window = w
+			w.stylesheets[i] = style
+			if w.started() == nil {
+				return nil
+			}
+			return w.Update()
+		}
+	}
+	return fmt.Errorf("the stylesheet to replace is not in the Window")
+}
+
+// StyleSheets are the stylesheets added to the Window
+func (w *Window) StyleSheets() []*StyleSheet {
+	return append([]*StyleSheet{}, w.stylesheets...)
+}
@@ -0,0 +1,89 @@
+package dali
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStyleSheetsInTheHead(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dali")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "app.css")
+	if err := ioutil.WriteFile(path, []byte("body{color:red}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := StyleSheetFile(filepath.Join(dir, "missing.css")); err == nil {
+		t.Errorf("expected a missing file to be an error")
+	}
+
+	url := StyleSheetURL("https://example.com/site.css?v=2")
+	file, err := StyleSheetFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := StyleSheetText("p{margin:0}</style><script>")
+	w := NewWindow(100, 100, "", "")
+	for _, s := range []*StyleSheet{url, file, text, url} {
+		if err := w.AddStyleSheet(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected := `<link rel="stylesheet" href="https://example.com/site.css?v=2"><style>body{color:red}</style><style>p{margin:0}<\/style><script></style>`
+	if head := w.head(); !strings.Contains(head, expected) {
+		t.Errorf(`expected the head to hold "%s" but got "%s"`, expected, head)
+	}
+
+	ui := &fakeUI{}
+	if err := w.run(ui, w.String()); err != nil {
+		t.Fatal(err)
+	}
+	if err := url.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if last := ui.evals[len(ui.evals)-1]; !strings.Contains(last, `site.css?v=2\u0026dali-reload=1`) {
+		t.Errorf(`expected the page to load the stylesheet again but got "%s"`, last)
+	}
+	inStep(t, w, "reloading a URL")
+
+	if err := ioutil.WriteFile(path, []byte("body{color:blue}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := file.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if last := ui.evals[len(ui.evals)-1]; !strings.Contains(last, "body{color:blue}") {
+		t.Errorf(`expected the page to get the new CSS of the file but got "%s"`, last)
+	}
+	inStep(t, w, "reloading a file")
+
+	dark := StyleSheetText("body{background:black}")
+	if err := w.ReplaceStyleSheet(text, dark); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.ReplaceStyleSheet(text, dark); err == nil {
+		t.Errorf("expected replacing a stylesheet that is not in the Window to be an error")
+	}
+	if sheets := w.StyleSheets(); len(sheets) != 3 || sheets[2] != dark || text.window != nil {
+		t.Errorf("expected the replacement to take the place of the old stylesheet")
+	}
+	inStep(t, w, "replacing a stylesheet")
+	if err := text.SetText("body{}"); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(w.head(), "body{}") {
+		t.Errorf("expected a removed stylesheet to no longer change the page")
+	}
+	if err := w.RemoveStyleSheet(url); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(w.head(), "site.css") {
+		t.Errorf("expected the removed stylesheet to leave the head")
+	}
+	inStep(t, w, "removing a stylesheet")
+}
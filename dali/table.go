@@ -0,0 +1,447 @@
+package dali
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// RowProvider supplies the rows of a Table
+type RowProvider interface {
+	Len() int
+	Row(i int) interface{}
+}
+
+// sliceRows provides the elements of a slice as rows
+type sliceRows struct {
+	slice reflect.Value
+}
+
+// SliceRows provides the elements of a slice as the rows of a Table.  The
+// slice is shared, not copied, so changes to its elements show when the
+// Table is refreshed; a slice that grows or shrinks must be given again.
+func SliceRows(slice interface{}) (RowProvider, error) {
+	r := reflect.ValueOf(slice)
+	if r.Kind() != reflect.Slice && r.Kind() != reflect.Array {
+		return nil, fmt.Errorf("SliceRows needs a slice, not %T", slice)
+	}
+	return sliceRows{slice: r}, nil
+}
+
+// noRows is the RowProvider of a Table given none
+func noRows() RowProvider { return sliceRows{slice: reflect.ValueOf([]interface{}{})} }
+
+// Len is the length of the slice
+func (s sliceRows) Len() int { return s.slice.Len() }
+
+// Row is element i of the slice
+func (s sliceRows) Row(i int) interface{} { return s.slice.Index(i).Interface() }
+
+// TableColumn describes one column of a Table
+type TableColumn struct {
+	Title    string
+	Field    string                            // the struct field or map key shown, when Value is nil
+	Value    func(row interface{}) interface{} // computes the value shown
+	Format   func(value interface{}) string    // renders the value, fmt.Sprint by default
+	Less     func(a, b interface{}) bool       // orders values, by their natural order by default
+	Sortable bool
+}
+
+// value is what the column shows for row
+func (c TableColumn) value(row interface{}) interface{} {
+	if c.Value != nil {
+		return c.Value(row)
+	}
+	r := reflect.ValueOf(row)
+	for r.Kind() == reflect.Ptr || r.Kind() == reflect.Interface {
+		if r.IsNil() {
+			return nil
+		}
+		r = r.Elem()
+	}
+	switch r.Kind() {
+	case reflect.Struct:
+		if f := r.FieldByName(c.Field); f.IsValid() && f.CanInterface() {
+			return f.Interface()
+		}
+	case reflect.Map:
+		if f := r.MapIndex(reflect.ValueOf(c.Field)); f.IsValid() {
+			return f.Interface()
+		}
+	}
+	return nil
+}
+
+// text renders a value of the column
+func (c TableColumn) text(value interface{}) string {
+	if c.Format != nil {
+		return c.Format(value)
+	}
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+// less orders two values of the column
+func (c TableColumn) less(a, b interface{}) bool {
+	if c.Less != nil {
+		return c.Less(a, b)
+	}
+	return naturalLess(a, b)
+}
+
+// naturalLess orders numbers by value, bools false first, and anything else by its text
+func naturalLess(a, b interface{}) bool {
+	ra, rb := reflect.ValueOf(a), reflect.ValueOf(b)
+	if ra.IsValid() && rb.IsValid() && ra.Kind() == rb.Kind() {
+		switch ra.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return ra.Int() < rb.Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return ra.Uint() < rb.Uint()
+		case reflect.Float32, reflect.Float64:
+			return ra.Float() < rb.Float()
+		case reflect.Bool:
+			return !ra.Bool() && rb.Bool()
+		case reflect.String:
+			return ra.String() < rb.String()
+		}
+	}
+	if !ra.IsValid() || !rb.IsValid() {
+		return !ra.IsValid() && rb.IsValid()
+	}
+	return fmt.Sprint(a) < fmt.Sprint(b)
+}
+
+// Table shows rows of data under a row of column headings.  Clicking a
+// sortable heading sorts by it, clicking again reverses the order, and a
+// Table with a PageSize shows its rows a page at a time.
+type Table struct {
+	ID         string
+	StyleName  string
+	Columns    []TableColumn
+	PageSize   int                          // rows on each page, or 0 for every row
+	Key        func(row interface{}) string // identifies a row when sorting moves it; its index by default
+	rows       RowProvider
+	sortColumn int
+	descending bool
+	page       int
+	selected   int
+	onSelect   []func(index int, row interface{})
+	functions  map[string]string
+	lock       sync.Mutex
+	BaseElement
+}
+
+// NewTable creates a Table showing rows in the given columns
+func NewTable(name string, columns []TableColumn, rows RowProvider) *Table {
+	if rows == nil {
+		rows = noRows()
+	}
+	t := &Table{ID: name, Columns: columns, rows: rows, sortColumn: -1, selected: -1}
+	t.function("sort")
+	return t
+}
+
+// TableOf creates a Table showing a slice of structs, with a sortable column
+// for each exported field
+func TableOf(name string, slice interface{}) (*Table, error) {
+	rows, err := SliceRows(slice)
+	if err != nil {
+		return nil, err
+	}
+	columns := []TableColumn{}
+	t := reflect.TypeOf(slice).Elem()
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Struct {
+		for i := 0; i < t.NumField(); i++ {
+			if f := t.Field(i); f.PkgPath == "" {
+				columns = append(columns, TableColumn{Title: f.Name, Field: f.Name, Sortable: true})
+			}
+		}
+	}
+	return NewTable(name, columns, rows), nil
+}
+
+// function is the name of the JavaScript function bound to one of sort, page and select
+func (t *Table) function(action string) string {
+	if t.functions == nil {
+		t.functions = map[string]string{}
+	}
+	if _, ok := t.functions[action]; !ok {
+		for _, a := range []string{"sort", "page", "select"} {
+			t.functions[a] = functionName("table_" + a)
+		}
+	}
+	return t.functions[action]
+}
+
+// String for Table
+func (t *Table) String() string {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	attrs := attribute("id", t.ID)
+	if t.StyleName != "" {
+		attrs += attribute("style", t.StyleName)
+	}
+
+	head := ""
+	for i, c := range t.Columns {
+		th := ""
+		if c.Sortable {
+			sorted := "none"
+			indicator := ""
+			if i == t.sortColumn {
+				sorted, indicator = "ascending", " &#9650;"
+				if t.descending {
+					sorted, indicator = "descending", " &#9660;"
+				}
+			}
+			th = attribute("aria-sort", sorted) + attribute("onclick", fmt.Sprintf("%s(%d)", t.function("sort"), i)) + ` style="cursor:pointer"`
+			th = fmt.Sprintf(`<th%s>%s%s</th>`, th, escape(c.Title), indicator)
+		} else {
+			th = fmt.Sprintf(`<th>%s</th>`, escape(c.Title))
+		}
+		head += th
+	}
+
+	body := ""
+	order := t.order()
+	first, last := t.pageBounds(len(order))
+	for _, i := range order[first:last] {
+		body += t.rowHTML(i)
+	}
+	return fmt.Sprintf(`<table%s%s%s><thead><tr>%s</tr></thead><tbody>%s</tbody>%s</table>`,
+		attrs, t.attributes(), t.Events.attributes(), head, body, t.footer(len(order)))
+}
+
+// rowHTML renders row i, which must be locked
+func (t *Table) rowHTML(i int) string {
+	row := t.provider().Row(i)
+	attrs := attribute("data-key", t.key(i, row)) + attribute("data-row", strconv.Itoa(i)) +
+		attribute("onclick", fmt.Sprintf("%s(%d)", t.function("select"), i))
+	if i == t.selected {
+		attrs += ` class="selected" aria-selected="true"`
+	}
+	cells := ""
+	for _, c := range t.Columns {
+		cells += fmt.Sprintf(`<td>%s</td>`, escape(c.text(c.value(row))))
+	}
+	return fmt.Sprintf(`<tr%s>%s</tr>`, attrs, cells)
+}
+
+// provider gives the rows, which must be locked.  A Table made without
+// NewTable has none.
+func (t *Table) provider() RowProvider {
+	if t.rows == nil {
+		t.rows = noRows()
+	}
+	return t.rows
+}
+
+// key identifies row i
+func (t *Table) key(i int, row interface{}) string {
+	if t.Key != nil {
+		return t.Key(row)
+	}
+	return strconv.Itoa(i)
+}
+
+// footer renders the page controls, which must be locked
+func (t *Table) footer(rows int) string {
+	pages := t.pages(rows)
+	if pages < 2 {
+		return ""
+	}
+	button := func(label string, page int, disabled bool) string {
+		attrs := attribute("onclick", fmt.Sprintf("%s(%d)", t.function("page"), page))
+		if disabled {
+			attrs += ` disabled`
+		}
+		return fmt.Sprintf(`<button type="button"%s>%s</button>`, attrs, label)
+	}
+	return fmt.Sprintf(`<tfoot><tr><td%s>%s<span>Page %d of %d</span>%s</td></tr></tfoot>`,
+		attribute("colspan", strconv.Itoa(len(t.Columns))),
+		button("&#8249;", t.page-1, t.page == 0), t.page+1, pages, button("&#8250;", t.page+1, t.page == pages-1))
+}
+
+// order is the index of every row in the order shown, which must be locked
+func (t *Table) order() []int {
+	order := make([]int, t.provider().Len())
+	for i := range order {
+		order[i] = i
+	}
+	if t.sortColumn < 0 || t.sortColumn >= len(t.Columns) {
+		return order
+	}
+	c := t.Columns[t.sortColumn]
+	values := make([]interface{}, len(order))
+	for i := range values {
+		values[i] = c.value(t.provider().Row(i))
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		if t.descending {
+			return c.less(values[order[b]], values[order[a]])
+		}
+		return c.less(values[order[a]], values[order[b]])
+	})
+	return order
+}
+
+// pages is the number of pages holding rows, which must be locked
+func (t *Table) pages(rows int) int {
+	if t.PageSize <= 0 {
+		return 1
+	}
+	return (rows + t.PageSize - 1) / t.PageSize
+}
+
+// pageBounds are the first and last positions of the page shown, which must be locked
+func (t *Table) pageBounds(rows int) (int, int) {
+	if t.PageSize <= 0 {
+		return 0, rows
+	}
+	if pages := t.pages(rows); t.page >= pages {
+		t.page = pages - 1
+	}
+	if t.page < 0 {
+		t.page = 0
+	}
+	first := t.page * t.PageSize
+	last := first + t.PageSize
+	if last > rows {
+		last = rows
+	}
+	return first, last
+}
+
+// Name of the Table
+func (t *Table) Name() string { return t.ID }
+
+// Style of the Table
+func (t *Table) Style() string { return t.StyleName }
+
+// Styles of the Table
+func (t *Table) Styles() Styles { return parseStyles(t.StyleName) }
+
+// SetRows replaces the rows of the Table
+func (t *Table) SetRows(rows RowProvider) error {
+	if rows == nil {
+		rows = noRows()
+	}
+	t.lock.Lock()
+	t.rows = rows
+	t.selected = -1
+	t.lock.Unlock()
+	return t.Refresh()
+}
+
+// SortBy sorts the rows by column, in descending order if descending is true
+func (t *Table) SortBy(column int, descending bool) error {
+	t.lock.Lock()
+	t.sortColumn, t.descending, t.page = column, descending, 0
+	t.lock.Unlock()
+	return t.Refresh()
+}
+
+// SetPage shows the page at index, counting from zero
+func (t *Table) SetPage(page int) error {
+	t.lock.Lock()
+	t.page = page
+	t.lock.Unlock()
+	return t.Refresh()
+}
+
+// Page is the index of the page shown, counting from zero
+func (t *Table) Page() int {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return t.page
+}
+
+// Selected is the index of the selected row, or -1 when none is
+func (t *Table) Selected() int {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return t.selected
+}
+
+// OnSelect calls f with the index and value of a row when the user clicks it
+func (t *Table) OnSelect(f func(index int, row interface{})) {
+	t.onSelect = append(t.onSelect, f)
+}
+
+// UpdateRow shows the current value of row i, replacing only that row in the
+// page.  Call Refresh instead when the change may move the row in the sort order.
+func (t *Table) UpdateRow(i int) error {
+	t.lock.Lock()
+	if i < 0 || i >= t.provider().Len() {
+		t.lock.Unlock()
+		return fmt.Errorf("Table %s has no row %d", t.ID, i)
+	}
+	html := t.rowHTML(i)
+	t.lock.Unlock()
+	row := strconv.Itoa(i)
+	return t.live.change(func(tr *tree, n *vnode) {
+		if old := n.find(func(c *vnode) bool {
+			return c.tag == "tr" && c.attrs["data-row"] == row && c.parent.tag == "tbody"
+		}); old != nil {
+			tr.replace(old, parseHTML(html)...)
+		}
+	}, `var r=el.querySelector('tbody > tr[data-row="'+%s+'"]');if(r){r.outerHTML=%s;}`, i, html)
+}
+
+// Refresh brings the page up to date with the rows, changing only what
+// differs.  Only the Table is rendered again, not the rest of the Window.
+func (t *Table) Refresh() error {
+	return t.live.patch(t.String)
+}
+
+// SetStyle sets a CSS property of the Table, or removes it if value is empty
+func (t *Table) SetStyle(property, value string) error {
+	return t.live.setStyle(&t.StyleName, property, value)
+}
+
+// Show displays the Table
+func (t *Table) Show() error { return t.live.show(&t.StyleName) }
+
+// Hide hides the Table
+func (t *Table) Hide() error { return t.live.hide(&t.StyleName) }
+
+// boundHandlers binds sorting, paging and selection, along with the handlers registered with On
+func (t *Table) boundHandlers() map[string]interface{} {
+	handlers := t.Events.boundHandlers()
+	handlers[t.function("sort")] = func(column int) error {
+		t.lock.Lock()
+		if t.sortColumn == column {
+			t.descending = !t.descending
+		} else {
+			t.sortColumn, t.descending = column, false
+		}
+		t.page = 0
+		t.lock.Unlock()
+		return t.Refresh()
+	}
+	handlers[t.function("page")] = t.SetPage
+	handlers[t.function("select")] = func(i int) error {
+		t.lock.Lock()
+		if i < 0 || i >= t.provider().Len() {
+			t.lock.Unlock()
+			return fmt.Errorf("Table %s has no row %d", t.ID, i)
+		}
+		t.selected = i
+		row := t.provider().Row(i)
+		t.lock.Unlock()
+		err := t.Refresh()
+		for _, f := range t.onSelect {
+			f(i, row)
+		}
+		return err
+	}
+	return handlers
+}
@@ -0,0 +1,55 @@
+package dali
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestTableOfRefusesNonSlices(t *testing.T) {
+	if _, err := TableOf("people", struct{ Name string }{"Ann"}); err == nil {
+		t.Errorf("expected an error for a struct")
+	}
+
+	table, err := TableOf("people", []struct{ Name string }{{"Ann"}})
+	if err != nil {
+		t.Errorf(`expected no error but got "%s"`, err)
+	} else if len(table.Columns) != 1 || table.Columns[0].Field != "Name" {
+		t.Errorf(`expected a Name column but got %v`, table.Columns)
+	}
+}
+
+func TestTablePatchesOnlyItself(t *testing.T) {
+	table, err := TableOf("people", []struct{ Name string }{{"Cy"}, {"Ann"}, {"Bo"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := &Span{ID: "other", Text: "same"}
+	ui := &fakeUI{}
+	w := startedWindow(t, ui, table, other)
+	shown := len(ui.evals)
+	handlers := table.boundHandlers()
+
+	if err := handlers[table.function("select")].(func(int) error)(1); err != nil {
+		t.Fatal(err)
+	}
+	path := w.rendered.ids["people"].path()
+	row, _ := json.Marshal(append(path, 1, 1))
+	expected := fmt.Sprintf(`dali.patch([{"op":"attr","path":%s,"name":"class","value":"selected"},{"op":"attr","path":%s,"name":"aria-selected","value":"true"}])`, row, row)
+	if patched := ui.evals[shown:]; len(patched) != 1 || patched[0] != expected {
+		t.Errorf(`expected "%s" but got %v`, expected, patched)
+	}
+
+	// rendering the rest of the Window again would find this change
+	other.Text = "changed"
+	if err := handlers[table.function("sort")].(func(int) error)(0); err != nil {
+		t.Fatal(err)
+	}
+	if patched := ui.evals[len(ui.evals)-1]; strings.Contains(patched, "changed") || !strings.Contains(patched, `"op":"move"`) {
+		t.Errorf(`expected the rows to be moved but got "%s"`, patched)
+	}
+	if html, expected := w.rendered.ids["people"].html(), parseHTML(table.String())[0].html(); html != expected {
+		t.Errorf(`expected "%s" but got "%s"`, expected, html)
+	}
+}
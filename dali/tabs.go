@@ -0,0 +1,270 @@
+package dali
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Tab is one titled page of a TabSet
+type Tab struct {
+	Key      string // identifies the tab in its TabSet
+	Title    string
+	Closable bool
+	Content  Element
+}
+
+// TabSet shows titled pages one at a time, under a strip of tabs that choose
+// between them.  The active tab is kept in Go; tabs can be added and closed
+// while the Window is running.
+type TabSet struct {
+	ID        string
+	StyleName string
+	tabs      []*Tab
+	panes     *Elements
+	active    int
+	count     int
+	onChange  []func(index int, tab *Tab)
+	onClose   []func(tab *Tab)
+	functions map[string]string
+	lock      sync.Mutex
+	BaseElement
+}
+
+// NewTabSet creates a TabSet with no tabs
+func NewTabSet(name string) *TabSet {
+	ts := &TabSet{ID: name, panes: &Elements{slice: []*Element{}}, active: -1}
+	ts.function("select")
+	return ts
+}
+
+// function is the name of the JavaScript function bound to selecting or closing a tab
+func (ts *TabSet) function(action string) string {
+	if ts.functions == nil {
+		ts.functions = map[string]string{
+			"select": functionName("tab_select"),
+			"close":  functionName("tab_close"),
+		}
+	}
+	return ts.functions[action]
+}
+
+// String for TabSet
+func (ts *TabSet) String() string {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+	attrs := attribute("id", ts.ID)
+	if ts.StyleName != "" {
+		attrs += attribute("style", ts.StyleName)
+	}
+	strip, panes := "", ""
+	for i, t := range ts.tabs {
+		tab := fmt.Sprintf("%s-tab-%s", ts.ID, t.Key)
+		pane := fmt.Sprintf("%s-pane-%s", ts.ID, t.Key)
+		selected := i == ts.active
+		close := ""
+		if t.Closable {
+			close = fmt.Sprintf(`<span class="dali-tab-close"%s%s>&#215;</span>`, attribute("aria-label", "Close "+t.Title),
+				attribute("onclick", fmt.Sprintf("event.stopPropagation();%s(%s)", ts.function("close"), jsString(t.Key))))
+		}
+		strip += fmt.Sprintf(`<button type="button" role="tab"%s%s%s%s%s>%s%s</button>`,
+			attribute("id", tab), attribute("data-key", t.Key), attribute("aria-controls", pane),
+			attribute("aria-selected", fmt.Sprint(selected)),
+			attribute("onclick", fmt.Sprintf("%s(%s)", ts.function("select"), jsString(t.Key))),
+			escape(t.Title), close)
+		hidden := ""
+		if !selected {
+			hidden = ` hidden`
+		}
+		content := ""
+		if t.Content != nil {
+			content = t.Content.String()
+		}
+		panes += fmt.Sprintf(`<div role="tabpanel"%s%s%s%s>%s</div>`,
+			attribute("id", pane), attribute("data-key", t.Key), attribute("aria-labelledby", tab), hidden, content)
+	}
+	return fmt.Sprintf(`<div%s%s%s><div role="tablist" class="dali-tab-strip">%s</div>%s</div>`,
+		attrs, ts.attributes(), ts.Events.attributes(), strip, panes)
+}
+
+// Name of the TabSet
+func (ts *TabSet) Name() string { return ts.ID }
+
+// Style of the TabSet
+func (ts *TabSet) Style() string { return ts.StyleName }
+
+// Styles of the TabSet
+func (ts *TabSet) Styles() Styles { return parseStyles(ts.StyleName) }
+
+// Children returns the contents of the tabs
+func (ts *TabSet) Children() *Elements { return ts.panes }
+
+// Add adds a tab showing content after the others, making it active if it is the first
+func (ts *TabSet) Add(title string, content Element, closable bool) (*Tab, error) {
+	ts.lock.Lock()
+	ts.count++
+	t := &Tab{Key: fmt.Sprint(ts.count), Title: title, Closable: closable, Content: content}
+	ts.tabs = append(ts.tabs, t)
+	ts.syncPanes()
+	first := ts.active < 0
+	if first {
+		ts.active = 0
+	}
+	ts.lock.Unlock()
+	if err := ts.refresh(); err != nil {
+		return t, err
+	}
+	if first {
+		ts.changed()
+	}
+	return t, nil
+}
+
+// Len is the number of tabs
+func (ts *TabSet) Len() int {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+	return len(ts.tabs)
+}
+
+// Tab returns the tab at index i
+func (ts *TabSet) Tab(i int) *Tab {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+	return ts.tabs[i]
+}
+
+// Active is the index of the active tab, or -1 when there are no tabs
+func (ts *TabSet) Active() int {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+	return ts.active
+}
+
+// Select makes the tab at index i active
+func (ts *TabSet) Select(i int) error {
+	ts.lock.Lock()
+	if i < 0 || i >= len(ts.tabs) {
+		ts.lock.Unlock()
+		return fmt.Errorf("TabSet %s has no tab %d", ts.ID, i)
+	}
+	if i == ts.active {
+		ts.lock.Unlock()
+		return nil
+	}
+	ts.active = i
+	ts.lock.Unlock()
+	err := ts.refresh()
+	ts.changed()
+	return err
+}
+
+// Close removes the tab at index i.  If it was active, the tab after it
+// becomes active, or the one before it if it was the last.
+func (ts *TabSet) Close(i int) error {
+	ts.lock.Lock()
+	if i < 0 || i >= len(ts.tabs) {
+		ts.lock.Unlock()
+		return fmt.Errorf("TabSet %s has no tab %d", ts.ID, i)
+	}
+	closed := ts.tabs[i]
+	ts.tabs = append(ts.tabs[:i], ts.tabs[i+1:]...)
+	ts.syncPanes()
+	wasActive := i == ts.active
+	if i < ts.active || ts.active >= len(ts.tabs) {
+		ts.active--
+	}
+	ts.lock.Unlock()
+
+	err := ts.refresh()
+	for _, f := range ts.onClose {
+		f(closed)
+	}
+	if wasActive {
+		ts.changed()
+	}
+	return err
+}
+
+// OnChange calls f with the index and tab that became active whenever the active tab changes
+func (ts *TabSet) OnChange(f func(index int, tab *Tab)) {
+	ts.onChange = append(ts.onChange, f)
+}
+
+// OnClose calls f with each tab that is closed
+func (ts *TabSet) OnClose(f func(tab *Tab)) {
+	ts.onClose = append(ts.onClose, f)
+}
+
+// changed tells the OnChange callbacks about the active tab
+func (ts *TabSet) changed() {
+	ts.lock.Lock()
+	i := ts.active
+	var t *Tab
+	if i >= 0 {
+		t = ts.tabs[i]
+	}
+	ts.lock.Unlock()
+	for _, f := range ts.onChange {
+		f(i, t)
+	}
+}
+
+// syncPanes keeps the Elements of the contents in step with the tabs, which must be locked
+func (ts *TabSet) syncPanes() {
+	ts.panes.slice = []*Element{}
+	for _, t := range ts.tabs {
+		if t.Content != nil {
+			content := t.Content
+			ts.panes.slice = append(ts.panes.slice, &content)
+		}
+	}
+}
+
+// index finds the tab with key, or -1
+func (ts *TabSet) index(key string) int {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+	for i, t := range ts.tabs {
+		if t.Key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// refresh brings the page up to date with the tabs
+func (ts *TabSet) refresh() error {
+	if !ts.live.running() {
+		return nil
+	}
+	return ts.live.window.Update()
+}
+
+// SetStyle sets a CSS property of the TabSet, or removes it if value is empty
+func (ts *TabSet) SetStyle(property, value string) error {
+	return ts.live.setStyle(&ts.StyleName, property, value)
+}
+
+// Show displays the TabSet
+func (ts *TabSet) Show() error { return ts.live.show(&ts.StyleName) }
+
+// Hide hides the TabSet
+func (ts *TabSet) Hide() error { return ts.live.hide(&ts.StyleName) }
+
+// boundHandlers binds selecting and closing tabs, along with the handlers registered with On
+func (ts *TabSet) boundHandlers() map[string]interface{} {
+	handlers := ts.Events.boundHandlers()
+	handlers[ts.function("select")] = func(key string) error {
+		if i := ts.index(key); i >= 0 {
+			return ts.Select(i)
+		}
+		return nil
+	}
+	handlers[ts.function("close")] = func(key string) error {
+		if i := ts.index(key); i >= 0 {
+			return ts.Close(i)
+		}
+		return nil
+	}
+	return handlers
+}
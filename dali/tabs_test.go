@@ -0,0 +1,107 @@
+package dali
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// titles lists the titles of the tabs, the active one in brackets
+func titles(ts *TabSet) string {
+	shown := []string{}
+	for i := 0; i < ts.Len(); i++ {
+		title := ts.Tab(i).Title
+		if i == ts.Active() {
+			title = "[" + title + "]"
+		}
+		shown = append(shown, title)
+	}
+	return strings.Join(shown, " ")
+}
+
+func TestTabSetCloseChoosesTheActiveTab(t *testing.T) {
+	ts := NewTabSet("tabs")
+	changes := []string{}
+	ts.OnChange(func(i int, tab *Tab) {
+		title := "none"
+		if tab != nil {
+			title = tab.Title
+		}
+		changes = append(changes, fmt.Sprintf("%d:%s", i, title))
+	})
+	for _, title := range []string{"a", "b", "c", "d"} {
+		if _, err := ts.Add(title, Text(title), true); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ts.Select(1); err != nil {
+		t.Fatal(err)
+	}
+
+	steps := []struct {
+		close    int
+		expected string
+	}{
+		{1, "a [c] d"}, // the active tab gives way to the one after it
+		{0, "[c] d"},   // closing a tab before the active one keeps it active
+		{1, "[c]"},     // closing a tab after it does too
+		{0, ""},
+	}
+	for _, step := range steps {
+		if err := ts.Close(step.close); err != nil {
+			t.Fatal(err)
+		}
+		if shown := titles(ts); shown != step.expected {
+			t.Errorf(`after closing %d expected "%s" but got "%s"`, step.close, step.expected, shown)
+		}
+	}
+	if ts.Active() != -1 {
+		t.Errorf("expected no active tab but got %d", ts.Active())
+	}
+	expected := "[0:a 1:b 1:c -1:none]"
+	if fmt.Sprint(changes) != expected {
+		t.Errorf(`expected "%s" but got "%v"`, expected, changes)
+	}
+}
+
+func TestTabSetCloseLastActiveTab(t *testing.T) {
+	ts := NewTabSet("tabs")
+	for _, title := range []string{"a", "b", "c"} {
+		ts.Add(title, Text(title), true)
+	}
+	ts.Select(2)
+	if err := ts.Close(2); err != nil {
+		t.Fatal(err)
+	}
+	if shown := titles(ts); shown != "a [b]" {
+		t.Errorf(`expected "%s" but got "%s"`, "a [b]", shown)
+	}
+	if err := ts.Close(5); err == nil {
+		t.Errorf("expected an error closing a tab that is not there")
+	}
+}
+
+func TestTabSetFromThePage(t *testing.T) {
+	ts := NewTabSet("tabs")
+	a, _ := ts.Add("a", Text("first"), false)
+	b, _ := ts.Add("b", Text("second"), true)
+	w := startedWindow(t, &fakeUI{}, ts)
+	handlers := ts.boundHandlers()
+
+	if err := handlers[ts.function("select")].(func(string) error)(b.Key); err != nil {
+		t.Fatal(err)
+	}
+	html := ts.String()
+	if ts.Active() != 1 || !strings.Contains(html, `hidden>first`) || strings.Contains(html, `hidden>second`) {
+		t.Errorf(`expected the second tab to be shown but got "%s"`, html)
+	}
+	inStep(t, w, "selecting a tab")
+
+	if err := handlers[ts.function("close")].(func(string) error)(b.Key); err != nil {
+		t.Fatal(err)
+	}
+	if ts.Len() != 1 || ts.Tab(0) != a || ts.Active() != 0 || ts.Children().Len() != 1 {
+		t.Errorf(`expected only the first tab to be left but got "%s"`, titles(ts))
+	}
+	inStep(t, w, "closing a tab")
+}
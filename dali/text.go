@@ -0,0 +1,29 @@
+package dali
+
+//TextElement is an element for plain old text - if you want style, use a Span
+type TextElement struct {
+	text string
+	BaseElement
+}
+
+//Text creates a TextElement
+func Text(t string) *TextElement {
+	return &TextElement{text: t}
+}
+
+//String stringer for TextElement, escaped so the text is never read as markup
+func (t *TextElement) String() string { return escape(t.text) }
+
+//RawHTMLElement is trusted markup rendered exactly as given - never use it for user content
+type RawHTMLElement struct {
+	html string
+	BaseElement
+}
+
+//RawHTML creates a RawHTMLElement
+func RawHTML(html string) *RawHTMLElement {
+	return &RawHTMLElement{html: html}
+}
+
+//String stringer for RawHTMLElement
+func (r *RawHTMLElement) String() string { return r.html }
@@ -0,0 +1,270 @@
+package dali
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// themeName matches the names a Theme can have
+var themeName = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// Theme is a named set of CSS custom properties, such as --background, that
+// stylesheets use with var(--background).  A Window shows one Theme at a
+// time, set as the data-theme attribute of the document.
+type Theme struct {
+	Name       string
+	properties Styles
+	window     *Window
+	lock       sync.Mutex // guards the properties and window, as handlers may Set while the page is rendered
+}
+
+// NewTheme creates a Theme setting properties, whose names are given the --
+// of custom properties if they do not have it
+func NewTheme(name string, properties Styles) *Theme {
+	t := &Theme{Name: name, properties: Styles{}}
+	for property, value := range properties {
+		t.properties[customProperty(property)] = value
+	}
+	return t
+}
+
+// customProperty is property as a CSS custom property name
+func customProperty(property string) string {
+	if strings.HasPrefix(property, "--") {
+		return property
+	}
+	return "--" + property
+}
+
+// Set sets a property of the Theme, or removes it if value is empty.  A value
+// that would end the rule of the Theme, such as one holding a ; or a }, is an error.
+func (t *Theme) Set(property, value string) error {
+	if err := checkDeclaration(customProperty(property), value); err != nil {
+		return err
+	}
+	t.lock.Lock()
+	if value == "" {
+		delete(t.properties, customProperty(property))
+	} else {
+		t.properties[customProperty(property)] = value
+	}
+	w := t.window
+	t.lock.Unlock()
+	if w == nil || w.started() == nil {
+		return nil
+	}
+	return w.Update()
+}
+
+// Get returns a property of the Theme
+func (t *Theme) Get(property string) string {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return t.properties[customProperty(property)]
+}
+
+// String renders the rule setting the properties while the Theme is shown
+func (t *Theme) String() string {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return strings.Replace(fmt.Sprintf(`:root[data-theme="%s"]{%s}`, t.Name, t.properties), "</", `<\/`, -1)
+}
+
+// setWindow records the Window the Theme is added to, or nil once it is replaced
+func (t *Theme) setWindow(w *Window) {
+	t.lock.Lock()
+	t.window = w
+	t.lock.Unlock()
+}
+
+// AddTheme makes a Theme available to SetTheme.  The first Theme added is
+// shown until another is set.
+func (w *Window) AddTheme(theme *Theme) error {
+	if !themeName.MatchString(theme.Name) {
+		return fmt.Errorf("%q is not a theme name: use letters, digits, - and _", theme.Name)
+	}
+	theme.lock.Lock()
+	for property, value := range theme.properties {
+		if err := checkDeclaration(property, value); err != nil {
+			theme.lock.Unlock()
+			return err
+		}
+	}
+	theme.lock.Unlock()
+	w.themeLock.Lock()
+	for i, t := range w.themes {
+		if t.Name == theme.Name {
+			t.setWindow(nil)
+			w.themes = append(w.themes[:i], w.themes[i+1:]...)
+			break
+		}
+	}
+	theme.setWindow(w)
+	w.themes = append(w.themes, theme)
+	if w.theme == "" {
+		w.theme = theme.Name
+	}
+	w.themeLock.Unlock()
+	if w.started() == nil {
+		return nil
+	}
+	return w.Update()
+}
+
+// Theme is the name of the Theme shown
+func (w *Window) Theme() string {
+	w.themeLock.Lock()
+	defer w.themeLock.Unlock()
+	return w.theme
+}
+
+// Themes are the names of the Themes added to the Window, sorted
+func (w *Window) Themes() []string {
+	w.themeLock.Lock()
+	defer w.themeLock.Unlock()
+	names := []string{}
+	for _, t := range w.themes {
+		names = append(names, t.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetTheme shows the named Theme, switching the running page straight away.
+// It stops the Window following the system color scheme.
+func (w *Window) SetTheme(name string) error {
+	w.themeLock.Lock()
+	light, dark := w.followLight, w.followDark
+	w.followLight, w.followDark = "", ""
+	w.themeLock.Unlock()
+	err := w.showTheme(name)
+	if err != nil && w.Theme() != name {
+		w.themeLock.Lock()
+		w.followLight, w.followDark = light, dark
+		w.themeLock.Unlock()
+	}
+	return err
+}
+
+// showTheme shows the named Theme
+func (w *Window) showTheme(name string) error {
+	w.themeLock.Lock()
+	found := false
+	for _, t := range w.themes {
+		found = found || t.Name == name
+	}
+	if !found {
+		w.themeLock.Unlock()
+		return fmt.Errorf("the Window has no theme %q", name)
+	}
+	w.theme = name
+	w.themeLock.Unlock()
+	if w.started() == nil {
+		return nil
+	}
+	return w.Update()
+}
+
+// PrefersDark asks the page whether the system prefers a dark color scheme
+func (w *Window) PrefersDark() (bool, error) {
+	if w.started() == nil {
+		return false, fmt.Errorf("Window has not been started")
+	}
+	v := w.started().Eval(`window.matchMedia("(prefers-color-scheme: dark)").matches`)
+	return v.Bool(), v.Err()
+}
+
+// FollowSystemTheme shows the light Theme while the system prefers a light
+// color scheme and the dark one while it prefers dark, switching whenever the
+// preference changes, until SetTheme is called
+func (w *Window) FollowSystemTheme(light, dark string) error {
+	w.themeLock.Lock()
+	w.followLight, w.followDark = light, dark
+	w.themeLock.Unlock()
+	if w.started() == nil {
+		// Start applies the preference
+		return nil
+	}
+	return w.followSystemTheme()
+}
+
+// followSystemTheme records the current system preference and shows its
+// Theme, if the Window follows it.  The OnColorSchemeChange callbacks are not
+// called, as the preference has not changed.
+func (w *Window) followSystemTheme() error {
+	dark, err := w.PrefersDark()
+	if err != nil {
+		return err
+	}
+	w.themeLock.Lock()
+	w.dark, w.schemeKnown = dark, true
+	name := w.followLight
+	if dark {
+		name = w.followDark
+	}
+	w.themeLock.Unlock()
+	if name == "" {
+		return nil
+	}
+	return w.showTheme(name)
+}
+
+// OnColorSchemeChange calls f, with dark true if the system now prefers a
+// dark color scheme, whenever the preference changes
+func (w *Window) OnColorSchemeChange(f func(dark bool)) {
+	w.themeLock.Lock()
+	defer w.themeLock.Unlock()
+	w.onColorScheme = append(w.onColorScheme, f)
+}
+
+// colorSchemeChanged is told by the page when the system color scheme changes
+func (w *Window) colorSchemeChanged(dark bool) error {
+	w.themeLock.Lock()
+	if w.schemeKnown && w.dark == dark {
+		w.themeLock.Unlock()
+		return nil
+	}
+	w.dark, w.schemeKnown = dark, true
+	name := w.followLight
+	if dark {
+		name = w.followDark
+	}
+	callbacks := append([]func(bool){}, w.onColorScheme...)
+	w.themeLock.Unlock()
+
+	var err error
+	if name != "" {
+		err = w.showTheme(name)
+	}
+	for _, f := range callbacks {
+		f(dark)
+	}
+	return err
+}
+
+// themeCSS renders the properties of every Theme, each applying while it is shown
+func (w *Window) themeCSS() string {
+	w.themeLock.Lock()
+	defer w.themeLock.Unlock()
+	if len(w.themes) == 0 {
+		return ""
+	}
+	css := ""
+	for _, t := range w.themes {
+		css += t.String()
+	}
+	return fmt.Sprintf(`<style id="dali-themes">%s</style>`, css)
+}
+
+// themeAttribute renders the data-theme attribute of the document
+func (w *Window) themeAttribute() string {
+	w.themeLock.Lock()
+	defer w.themeLock.Unlock()
+	if w.theme == "" {
+		return ""
+	}
+	return attribute("data-theme", w.theme)
+}
@@ -0,0 +1,59 @@
+package dali
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestThemeSetWhileRendering(t *testing.T) {
+	theme := NewTheme("light", Styles{"background": "white"})
+	w := startedWindow(t, &fakeUI{})
+	if err := w.AddTheme(theme); err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			theme.Set("background", "black")
+		}()
+		go func() {
+			defer wg.Done()
+			w.Update()
+		}()
+	}
+	wg.Wait()
+	if css := w.themeCSS(); !strings.Contains(css, "--background:black") {
+		t.Errorf(`expected "%s" but got "%s"`, "--background:black", css)
+	}
+}
+
+func TestThemeRefusesBreakingOut(t *testing.T) {
+	theme := NewTheme("light", nil)
+	if err := theme.Set("background", "red}body{display:none"); err == nil {
+		t.Errorf("expected an error for a value ending the rule")
+	}
+	if theme.Get("background") != "" {
+		t.Errorf(`expected no background but got "%s"`, theme.Get("background"))
+	}
+}
+
+func TestSetThemeShowsTheTheme(t *testing.T) {
+	w := startedWindow(t, &fakeUI{})
+	w.AddTheme(NewTheme("light", Styles{"background": "white"}))
+	w.AddTheme(NewTheme("dark", Styles{"background": "black"}))
+	if w.Theme() != "light" {
+		t.Errorf(`expected "%s" but got "%s"`, "light", w.Theme())
+	}
+	if err := w.SetTheme("dark"); err != nil {
+		t.Fatal(err)
+	}
+	if attr := w.themeAttribute(); attr != ` data-theme="dark"` {
+		t.Errorf(`expected "%s" but got "%s"`, ` data-theme="dark"`, attr)
+	}
+	if err := w.SetTheme("blue"); err == nil || w.Theme() != "dark" {
+		t.Errorf(`expected an error and "%s" but got "%s"`, "dark", w.Theme())
+	}
+}
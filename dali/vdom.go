@@ -0,0 +1,640 @@
+package dali
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// vnode is a node of a rendered page: an element, a text node when tag is
+// empty, or a comment when tag is commentTag
+type vnode struct {
+	tag      string
+	attrs    map[string]string
+	names    []string // attribute names in document order
+	children []*vnode
+	parent   *vnode
+	text     string // the text of a text node or comment
+}
+
+// commentTag is the tag of a comment node
+const commentTag = "#comment"
+
+// key identifies a node among its siblings: its data-key, or else its id
+func (n *vnode) key() string {
+	if k, ok := n.attrs["data-key"]; ok {
+		return k
+	}
+	return n.attrs["id"]
+}
+
+// setAttr sets an attribute of n, adding it after the others if it is new
+func (n *vnode) setAttr(name, value string) {
+	if _, ok := n.attrs[name]; !ok {
+		n.names = append(n.names, name)
+	}
+	n.attrs[name] = value
+}
+
+// removeAttr removes an attribute of n
+func (n *vnode) removeAttr(name string) {
+	if _, ok := n.attrs[name]; !ok {
+		return
+	}
+	delete(n.attrs, name)
+	for i, other := range n.names {
+		if other == name {
+			n.names = append(n.names[:i:i], n.names[i+1:]...)
+			break
+		}
+	}
+}
+
+// path locates n in the page: the index of each node leading to it among its siblings
+func (n *vnode) path() []int {
+	path := []int{}
+	for ; n.parent != nil; n = n.parent {
+		for i, c := range n.parent.children {
+			if c == n {
+				path = append([]int{i}, path...)
+				break
+			}
+		}
+	}
+	return path
+}
+
+// find returns the first node below n, in document order, that match accepts
+func (n *vnode) find(match func(*vnode) bool) *vnode {
+	for _, c := range n.children {
+		if match(c) {
+			return c
+		}
+		if found := c.find(match); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// each calls visit with every node below n, in document order
+func (n *vnode) each(visit func(*vnode)) {
+	for _, c := range n.children {
+		visit(c)
+		c.each(visit)
+	}
+}
+
+// html renders n as markup that parses back into the same node
+func (n *vnode) html() string {
+	switch n.tag {
+	case "":
+		return escape(n.text)
+	case commentTag:
+		return "<!--" + n.text + "-->"
+	}
+	var b strings.Builder
+	b.WriteString("<" + n.tag)
+	for _, name := range n.names {
+		b.WriteString(attribute(name, n.attrs[name]))
+	}
+	b.WriteString(">")
+	if voidElements[n.tag] {
+		return b.String()
+	}
+	for _, c := range n.children {
+		if rawTextElements[n.tag] && c.tag == "" {
+			b.WriteString(c.text)
+		} else {
+			b.WriteString(c.html())
+		}
+	}
+	b.WriteString("</" + n.tag + ">")
+	return b.String()
+}
+
+// voidElements never have content or a closing tag
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true,
+	"image": true, "input": true, "link": true, "meta": true, "source": true, "track": true, "wbr": true,
+}
+
+// rawTextElements hold text that is neither parsed as markup nor unescaped
+var rawTextElements = map[string]bool{"script": true, "style": true}
+
+// rcdataElements hold text that is not parsed as markup, but is unescaped
+var rcdataElements = map[string]bool{"title": true, "textarea": true}
+
+// parseHTML parses the markup rendered by dali elements into a tree of vnodes.
+// It is not a general HTML parser: dali always quotes and closes what it renders.
+func parseHTML(s string) []*vnode {
+	root := &vnode{}
+	stack := []*vnode{root}
+	top := func() *vnode { return stack[len(stack)-1] }
+
+	for pos := 0; pos < len(s); {
+		if s[pos] != '<' {
+			end := strings.IndexByte(s[pos:], '<')
+			if end < 0 {
+				end = len(s) - pos
+			}
+			appendText(top(), html.UnescapeString(s[pos:pos+end]))
+			pos += end
+			continue
+		}
+		if strings.HasPrefix(s[pos:], "<!--") {
+			end := strings.Index(s[pos+4:], "-->")
+			if end < 0 {
+				end = len(s) - pos - 4
+			}
+			appendChild(top(), &vnode{tag: commentTag, text: s[pos+4 : pos+4+end]})
+			pos += end + 7
+			continue
+		}
+		if strings.HasPrefix(s[pos:], "</") {
+			end := strings.IndexByte(s[pos:], '>')
+			if end < 0 {
+				break
+			}
+			tag := strings.ToLower(strings.TrimSpace(s[pos+2 : pos+end]))
+			pos += end + 1
+			for i := len(stack) - 1; i > 0; i-- {
+				if stack[i].tag == tag {
+					stack = stack[:i]
+					break
+				}
+			}
+			continue
+		}
+
+		start := pos
+		n, end := parseTag(s, pos)
+		if n == nil {
+			appendText(top(), "<")
+			pos++
+			continue
+		}
+		pos = end
+		appendChild(top(), n)
+		if voidElements[n.tag] || strings.HasSuffix(s[start:pos], "/>") {
+			continue
+		}
+		if rawTextElements[n.tag] || rcdataElements[n.tag] {
+			closing := strings.Index(strings.ToLower(s[pos:]), "</"+n.tag)
+			if closing < 0 {
+				closing = len(s) - pos
+			}
+			text := s[pos : pos+closing]
+			if rcdataElements[n.tag] {
+				text = html.UnescapeString(text)
+			}
+			appendText(n, text)
+			pos += closing
+			if gt := strings.IndexByte(s[pos:], '>'); gt >= 0 {
+				pos += gt + 1
+			}
+			continue
+		}
+		stack = append(stack, n)
+	}
+	for _, n := range root.children {
+		n.parent = nil
+	}
+	return root.children
+}
+
+// parseTag parses the start tag at pos, returning the node and the position after it
+func parseTag(s string, pos int) (*vnode, int) {
+	i := pos + 1
+	for i < len(s) && !strings.ContainsRune(" \t\n\r/>", rune(s[i])) {
+		i++
+	}
+	if i == pos+1 {
+		return nil, pos
+	}
+	n := &vnode{tag: strings.ToLower(s[pos+1 : i]), attrs: map[string]string{}}
+	for i < len(s) {
+		for i < len(s) && strings.ContainsRune(" \t\n\r/", rune(s[i])) {
+			i++
+		}
+		if i >= len(s) || s[i] == '>' {
+			return n, i + 1
+		}
+		nameStart := i
+		for i < len(s) && !strings.ContainsRune(" \t\n\r/>=", rune(s[i])) {
+			i++
+		}
+		name := strings.ToLower(s[nameStart:i])
+		value := ""
+		if i < len(s) && s[i] == '=' {
+			i++
+			if i < len(s) && (s[i] == '"' || s[i] == '\'') {
+				quote := s[i]
+				end := strings.IndexByte(s[i+1:], quote)
+				if end < 0 {
+					end = len(s) - i - 1
+				}
+				value = s[i+1 : i+1+end]
+				i += end + 2
+			} else {
+				valueStart := i
+				for i < len(s) && !strings.ContainsRune(" \t\n\r>", rune(s[i])) {
+					i++
+				}
+				value = s[valueStart:i]
+			}
+		}
+		if _, seen := n.attrs[name]; !seen && name != "" {
+			n.attrs[name] = html.UnescapeString(value)
+			n.names = append(n.names, name)
+		}
+	}
+	return n, len(s)
+}
+
+// appendText adds text to n, joining it with a text node before it as the browser does
+func appendText(n *vnode, text string) {
+	if text == "" {
+		return
+	}
+	if last := len(n.children) - 1; last >= 0 && n.children[last].tag == "" {
+		n.children[last].text += text
+		return
+	}
+	appendChild(n, &vnode{text: text})
+}
+
+// appendChild adds c as the last child of n
+func appendChild(n, c *vnode) {
+	c.parent = n
+	n.children = append(n.children, c)
+}
+
+// normalizeDocument arranges a parsed page the way the browser builds it: the
+// html element holds a head followed by a body, and everything else goes in the body
+func normalizeDocument(nodes []*vnode) *vnode {
+	var doc *vnode
+	for _, n := range nodes {
+		if n.tag == "html" {
+			doc = n
+		}
+	}
+	if doc == nil {
+		doc = &vnode{tag: "html", attrs: map[string]string{}, children: nodes}
+	}
+	var head, body *vnode
+	loose := []*vnode{}
+	for _, n := range doc.children {
+		switch {
+		case n.tag == "head" && head == nil:
+			head = n
+		case n.tag == "body" && body == nil:
+			body = n
+		default:
+			loose = append(loose, n)
+		}
+	}
+	if head == nil {
+		head = &vnode{tag: "head", attrs: map[string]string{}}
+	}
+	if body == nil {
+		body = &vnode{tag: "body", attrs: map[string]string{}}
+	}
+	for _, n := range loose {
+		appendChild(body, n)
+	}
+	doc.children = nil
+	appendChild(doc, head)
+	appendChild(doc, body)
+	doc.parent = nil
+	return doc
+}
+
+// tree is the page as the browser last received it, with its elements
+// indexed by id so a change to one element is found without a search
+type tree struct {
+	root *vnode
+	ids  map[string]*vnode
+}
+
+// newTree indexes the page rooted at root
+func newTree(root *vnode) *tree {
+	t := &tree{root: root, ids: map[string]*vnode{}}
+	t.index(root)
+	return t
+}
+
+// index adds n and every node below it to the ids, the first of a duplicated id winning
+func (t *tree) index(n *vnode) {
+	if id := n.attrs["id"]; id != "" {
+		if _, taken := t.ids[id]; !taken {
+			t.ids[id] = n
+		}
+	}
+	for _, c := range n.children {
+		t.index(c)
+	}
+}
+
+// unindex removes n and every node below it from the ids
+func (t *tree) unindex(n *vnode) {
+	if id := n.attrs["id"]; id != "" && t.ids[id] == n {
+		delete(t.ids, id)
+	}
+	for _, c := range n.children {
+		t.unindex(c)
+	}
+}
+
+// splice replaces count children of parent, from index i on, with nodes
+func (t *tree) splice(parent *vnode, i, count int, nodes ...*vnode) {
+	for _, old := range parent.children[i : i+count] {
+		t.unindex(old)
+		old.parent = nil
+	}
+	rest := append([]*vnode{}, parent.children[i+count:]...)
+	parent.children = append(append(parent.children[:i], nodes...), rest...)
+	for _, n := range nodes {
+		n.parent = parent
+		t.index(n)
+	}
+}
+
+// setChildren replaces every child of parent with nodes
+func (t *tree) setChildren(parent *vnode, nodes ...*vnode) {
+	t.splice(parent, 0, len(parent.children), nodes...)
+}
+
+// replace puts nodes in place of n
+func (t *tree) replace(n *vnode, nodes ...*vnode) {
+	if n.parent == nil {
+		return
+	}
+	for i, c := range n.parent.children {
+		if c == n {
+			t.splice(n.parent, i, 1, nodes...)
+			return
+		}
+	}
+}
+
+// textNodes is the content of an element whose textContent is set to text
+func textNodes(text string) []*vnode {
+	if text == "" {
+		return nil
+	}
+	return []*vnode{{text: text}}
+}
+
+// patch is one change to the page, applied by dali.patch in the order given
+type patch struct {
+	Op    string `json:"op"`
+	Path  []int  `json:"path"`
+	Index int    `json:"index,omitempty"`
+	From  int    `json:"from,omitempty"`
+	Name  string `json:"name,omitempty"`
+	Value string `json:"value,omitempty"`
+	HTML  string `json:"html,omitempty"`
+}
+
+// diff appends the patches turning old into new, where path locates old in the page
+func diff(old, new *vnode, path []int, patches []patch) []patch {
+	if old.tag != new.tag || (old.tag != "" && old.key() != new.key()) {
+		return append(patches, patch{Op: "replace", Path: path, HTML: new.html()})
+	}
+	if old.tag == "" || old.tag == commentTag {
+		if old.text != new.text {
+			patches = append(patches, patch{Op: "text", Path: path, Value: new.text})
+		}
+		return patches
+	}
+	for _, name := range new.names {
+		if value, ok := old.attrs[name]; !ok || value != new.attrs[name] {
+			patches = append(patches, patch{Op: "attr", Path: path, Name: name, Value: new.attrs[name]})
+		}
+	}
+	for _, name := range old.names {
+		if _, ok := new.attrs[name]; !ok {
+			patches = append(patches, patch{Op: "unattr", Path: path, Name: name})
+		}
+	}
+	if marked(old.children) && marked(new.children) {
+		return diffMarked(old.children, new.children, path, patches)
+	}
+	if keyed(old.children) && keyed(new.children) {
+		return diffKeyed(old.children, new.children, path, patches)
+	}
+	return diffChildren(old.children, new.children, path, patches)
+}
+
+// keyed is true when every node has a key and no two nodes share one
+func keyed(nodes []*vnode) bool {
+	keys := map[string]bool{}
+	for _, n := range nodes {
+		k := n.key()
+		if n.tag == "" || n.tag == commentTag || k == "" || keys[k] {
+			return false
+		}
+		keys[k] = true
+	}
+	return true
+}
+
+// diffChildren pairs children by position
+func diffChildren(old, new []*vnode, path []int, patches []patch) []patch {
+	i := 0
+	for ; i < len(old) && i < len(new); i++ {
+		patches = diff(old[i], new[i], childPath(path, i), patches)
+	}
+	for j := len(new); j < len(old); j++ {
+		patches = append(patches, patch{Op: "remove", Path: childPath(path, len(new))})
+	}
+	for ; i < len(new); i++ {
+		patches = append(patches, patch{Op: "insert", Path: path, Index: i, HTML: new[i].html()})
+	}
+	return patches
+}
+
+// diffKeyed pairs children by key, moving nodes rather than rebuilding them
+func diffKeyed(old, new []*vnode, path []int, patches []patch) []patch {
+	current := append([]*vnode{}, old...)
+	for i, n := range new {
+		found := -1
+		for j := i; j < len(current); j++ {
+			if current[j].key() == n.key() {
+				found = j
+				break
+			}
+		}
+		if found < 0 {
+			patches = append(patches, patch{Op: "insert", Path: path, Index: i, HTML: n.html()})
+			current = append(current[:i], append([]*vnode{n}, current[i:]...)...)
+			continue
+		}
+		if found != i {
+			patches = append(patches, patch{Op: "move", Path: path, From: found, Index: i})
+			moved := current[found]
+			current = append(current[:found], current[found+1:]...)
+			current = append(current[:i], append([]*vnode{moved}, current[i:]...)...)
+		}
+		patches = diff(current[i], n, childPath(path, i), patches)
+	}
+	for j := len(new); j < len(current); j++ {
+		patches = append(patches, patch{Op: "remove", Path: childPath(path, len(new))})
+	}
+	return patches
+}
+
+// segments splits nodes at the markers of an Elements: the nodes before the
+// first marker, then each marker with the nodes after it up to the next one
+func segments(nodes []*vnode) [][]*vnode {
+	segs := [][]*vnode{{}}
+	for _, n := range nodes {
+		if isMarker(n) {
+			segs = append(segs, []*vnode{})
+		}
+		segs[len(segs)-1] = append(segs[len(segs)-1], n)
+	}
+	return segs
+}
+
+// segmentKey is the marker starting a segment, or empty for the nodes before the first marker
+func segmentKey(seg []*vnode) string {
+	if len(seg) > 0 && isMarker(seg[0]) {
+		return seg[0].text
+	}
+	return ""
+}
+
+// marked is true when nodes hold the elements of an Elements, each marker appearing once
+func marked(nodes []*vnode) bool {
+	keys := map[string]bool{}
+	for _, n := range nodes {
+		if !isMarker(n) {
+			continue
+		}
+		if keys[n.text] {
+			return false
+		}
+		keys[n.text] = true
+	}
+	return len(keys) > 0
+}
+
+// diffMarked pairs the nodes of each element of an Elements by its marker,
+// moving them together rather than rebuilding them
+func diffMarked(old, new []*vnode, path []int, patches []patch) []patch {
+	current, want := segments(old), segments(new)
+	offset := 0
+	for i, seg := range want {
+		found := -1
+		for j := i; j < len(current); j++ {
+			if segmentKey(current[j]) == segmentKey(seg) {
+				found = j
+				break
+			}
+		}
+		if found < 0 {
+			html := ""
+			for _, n := range seg {
+				html += n.html()
+			}
+			if html != "" {
+				patches = append(patches, patch{Op: "insert", Path: path, Index: offset, HTML: html})
+			}
+			current = append(current[:i], append([][]*vnode{seg}, current[i:]...)...)
+			offset += len(seg)
+			continue
+		}
+		if found != i {
+			from := offset
+			for _, skipped := range current[i:found] {
+				from += len(skipped)
+			}
+			for k := range current[found] {
+				patches = append(patches, patch{Op: "move", Path: path, From: from + k, Index: offset + k})
+			}
+			moved := current[found]
+			current = append(current[:found], current[found+1:]...)
+			current = append(current[:i], append([][]*vnode{moved}, current[i:]...)...)
+		}
+		if len(current[i]) == len(seg) {
+			for k := range seg {
+				patches = diff(current[i][k], seg[k], childPath(path, offset+k), patches)
+			}
+		} else {
+			for range current[i] {
+				patches = append(patches, patch{Op: "remove", Path: childPath(path, offset)})
+			}
+			html := ""
+			for _, n := range seg {
+				html += n.html()
+			}
+			if html != "" {
+				patches = append(patches, patch{Op: "insert", Path: path, Index: offset, HTML: html})
+			}
+		}
+		offset += len(seg)
+	}
+	for _, seg := range current[len(want):] {
+		for range seg {
+			patches = append(patches, patch{Op: "remove", Path: childPath(path, offset)})
+		}
+	}
+	return patches
+}
+
+// childPath is the path of child i of the node at path
+func childPath(path []int, i int) []int {
+	return append(append([]int{}, path...), i)
+}
+
+// Update changes the running page to match the Elements of the Window.  The
+// tree is rendered and compared with what the page was last sent, and only the
+// differences are sent to the page, in a single Eval, so canvas contents and
+// input state are kept.  Siblings with a data-key or id keep their identity.
+func (w *Window) Update() error {
+	ui := w.started()
+	if ui == nil {
+		return fmt.Errorf("Window has not been started")
+	}
+	// elements are started first, as showing a page can itself update the Window
+	for _, el := range w.Elements.slice {
+		if err := start(*el); err != nil {
+			return err
+		}
+	}
+
+	w.updating.Lock()
+	defer w.updating.Unlock()
+	if w.Elements.window != w {
+		w.Elements.window = w
+	}
+	for _, el := range w.Elements.slice {
+		w.attach(*el)
+	}
+	w.bindChildren(nil)
+	if err := w.bindPending(); err != nil {
+		return err
+	}
+	doc := normalizeDocument(parseHTML(w.String()))
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	patches := diff(w.rendered.root, doc, []int{}, []patch{})
+	if len(patches) > 0 {
+		if err := Call(ui, "dali.patch", patches).Err(); err != nil {
+			return err
+		}
+	}
+	w.rendered = newTree(doc)
+	return nil
+}
+
+// Render replaces the Elements of the Window with els and updates the page to match
+func (w *Window) Render(els *Elements) error {
+	w.updating.Lock()
+	w.Elements = els
+	w.updating.Unlock()
+	return w.Update()
+}
@@ -0,0 +1,157 @@
+package dali
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestParseHTMLRendersBack(t *testing.T) {
+	for _, markup := range []string{
+		`<div id="a" class="x y"><p>one &amp; two</p><br><!--dali:1--><span title="&#34;quoted&#34;">text</span></div>`,
+		`<script>if (a < b && c > d) { go("</p>"); }</script>`,
+		`<style>p > a { color: red; }</style>`,
+		`<textarea>&lt;b&gt;</textarea>`,
+	} {
+		nodes := parseHTML(markup)
+		html := ""
+		for _, n := range nodes {
+			html += n.html()
+		}
+		if html != markup {
+			t.Errorf(`expected "%s" but got "%s"`, markup, html)
+		}
+	}
+}
+
+func TestParseHTMLKeepsScriptsRaw(t *testing.T) {
+	script := parseHTML(`<script>a &amp;&amp; b</script>`)[0]
+	if text := script.children[0].text; text != "a &amp;&amp; b" {
+		t.Errorf(`expected "%s" but got "%s"`, "a &amp;&amp; b", text)
+	}
+	title := parseHTML(`<title>a &amp; b</title>`)[0]
+	if text := title.children[0].text; text != "a & b" {
+		t.Errorf(`expected "%s" but got "%s"`, "a & b", text)
+	}
+}
+
+// applyPatches changes root as dali.patch changes the page
+func applyPatches(root *vnode, patches []patch) {
+	for _, p := range patches {
+		n := root
+		for _, i := range p.Path {
+			n = n.children[i]
+		}
+		switch p.Op {
+		case "replace":
+			siblings := n.parent.children
+			for i, c := range siblings {
+				if c == n {
+					n.parent.children = append(append(append([]*vnode{}, siblings[:i]...), adopted(n.parent, p.HTML)...), siblings[i+1:]...)
+					break
+				}
+			}
+		case "insert":
+			at := p.Index
+			if at > len(n.children) {
+				at = len(n.children)
+			}
+			n.children = append(append(append([]*vnode{}, n.children[:at]...), adopted(n, p.HTML)...), n.children[at:]...)
+		case "move":
+			// insertBefore finds the node it goes before, then takes the moved one out
+			moved := n.children[p.From]
+			var before *vnode
+			if p.Index < len(n.children) {
+				before = n.children[p.Index]
+			}
+			n.children = append(n.children[:p.From:p.From], n.children[p.From+1:]...)
+			at := len(n.children)
+			for i, c := range n.children {
+				if c == before {
+					at = i
+				}
+			}
+			n.children = append(n.children[:at:at], append([]*vnode{moved}, n.children[at:]...)...)
+		case "remove":
+			siblings := n.parent.children
+			for i, c := range siblings {
+				if c == n {
+					n.parent.children = append(siblings[:i:i], siblings[i+1:]...)
+					break
+				}
+			}
+		case "text":
+			n.text = p.Value
+		case "attr":
+			n.setAttr(p.Name, p.Value)
+		case "unattr":
+			n.removeAttr(p.Name)
+		}
+	}
+}
+
+// adopted parses markup into nodes whose parent is p
+func adopted(p *vnode, markup string) []*vnode {
+	nodes := parseHTML(markup)
+	for _, n := range nodes {
+		n.parent = p
+	}
+	return nodes
+}
+
+func TestDiffPatchesOldIntoNew(t *testing.T) {
+	for _, c := range []struct{ name, old, new string }{
+		{"text", `<body><p>one</p></body>`, `<body><p>two</p></body>`},
+		{"attributes", `<body><p id="a" class="x">one</p></body>`, `<body><p id="a" title="t">one</p></body>`},
+		{"tag", `<body><p>one</p></body>`, `<body><div>one</div></body>`},
+		{"added", `<body><p>one</p></body>`, `<body><p>one</p><p>two</p><p>three</p></body>`},
+		{"removed", `<body><p>one</p><p>two</p><p>three</p></body>`, `<body><p>one</p></body>`},
+		{"comment", `<body><!--a--></body>`, `<body><!--b--></body>`},
+		{"keyed", `<body><ul><li data-key="a">a</li><li data-key="b">b</li><li data-key="c">c</li></ul></body>`,
+			`<body><ul><li data-key="c">c</li><li data-key="a">A</li><li data-key="d">d</li></ul></body>`},
+		{"marked", `<body><div id="l"><!--dali:1--><p>a</p>x<!--dali:2--><p>b</p><!--dali:3--><p>c</p><!--/dali--></div></body>`,
+			`<body><div id="l"><!--dali:3--><p>c</p><!--dali:1--><p>A</p><!--dali:4--><p>d</p>y<!--/dali--></div></body>`},
+	} {
+		old := normalizeDocument(parseHTML(c.old))
+		new := normalizeDocument(parseHTML(c.new))
+		applyPatches(old, diff(old, new, []int{}, []patch{}))
+		if expected, html := new.html(), old.html(); html != expected {
+			t.Errorf(`%s: expected "%s" but got "%s"`, c.name, expected, html)
+		}
+	}
+}
+
+func TestDiffMovesKeyedNodes(t *testing.T) {
+	old := normalizeDocument(parseHTML(`<body><ul><li data-key="a">a</li><li data-key="b">b</li></ul></body>`))
+	new := normalizeDocument(parseHTML(`<body><ul><li data-key="b">b</li><li data-key="a">a</li></ul></body>`))
+	patches := diff(old, new, []int{}, []patch{})
+	if len(patches) != 1 || patches[0].Op != "move" {
+		t.Errorf(`expected a single move but got %v`, patches)
+	}
+}
+
+func TestUpdatesFromManyHandlers(t *testing.T) {
+	tabs := NewTabSet("tabs")
+	tabs.Add("One", Text("one"), false)
+	tabs.Add("Two", Text("two"), false)
+	table := NewTable("table", []TableColumn{{Title: "N", Field: "N", Sortable: true}}, nil)
+	startedWindow(t, &fakeUI{}, tabs, table)
+
+	// lorca calls each handler in a goroutine of its own
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			if err := tabs.Select(i % 2); err != nil {
+				t.Error(err)
+			}
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			if err := table.SortBy(0, i%2 == 0); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
@@ -6,111 +6,99 @@ Copyright (c) 2020 Matthew Peters
 
 import (
 	"fmt"
+	"log"
 	"math/rand"
 	"time"
 
 	"github.com/matthewapeters/dali"
-	"github.com/zserge/lorca"
 )
 
-func changeTitleD(ui lorca.UI, words string) {
-	ui.Eval(fmt.Sprintf(`document.getElementById("heading").innerHTML="%s";`, words))
-}
-
-func drawALineD(ui lorca.UI, x1, y1, x2, y2 float32) {
-	s := `
-var c = document.getElementById("whiteboard");
-var ctx = c.getContext("2d");
-ctx.moveTo(%f, %f);
-ctx.lineTo(%f, %f);
-ctx.stroke();
-document.getElementById("coords").innerHTML=%s;
-`
-	coords := fmt.Sprintf(`"(%3.2f, %3.2f) - (%3.2f, %3.2f)"`, x1, y1, x2, y2)
-	ui.Eval(fmt.Sprintf(s, x1, y1, x2, y2, coords))
-
+func drawALineD(ctx *dali.Context2D, coords *dali.Div, x1, y1, x2, y2 float64) error {
+	// the line is recorded and drawn with a single Flush
+	ctx.BeginPath()
+	ctx.MoveTo(x1, y1)
+	ctx.LineTo(x2, y2)
+	ctx.Stroke()
+	if err := ctx.Flush(); err != nil {
+		return err
+	}
+	return coords.SetText(fmt.Sprintf("(%3.2f, %3.2f) - (%3.2f, %3.2f)", x1, y1, x2, y2))
 }
 
-func drawAPictureD(ui lorca.UI) {
+func drawAPictureD(ctx *dali.Context2D) error {
 	url := "http://cdn.dumpaday.com/wp-content/uploads/2020/06/00-57-750x280.jpg"
-	s := `
-var c = document.getElementById("whiteboard");
-var ctx = c.getContext("2d");
-var img = new Image;
-img.onload=function(){
-	ctx.drawImage(img, 0,0)
-}
-img.src="%s"
-`
-	ui.Eval(fmt.Sprintf(s, url))
+	return ctx.DrawImage(url, 0, 0)
 }
 
 //DaliExample is a Dali version of the example
 func DaliExample() {
 	// Define some application variables
-	clicks := 0
-	var x1, y1, x2, y2 float32
+	// the heading shows the clicks, whichever goroutine changes them
+	clicks := dali.NewState(0)
+	var x1, y1, x2, y2 float64
 	clock := time.NewTicker(time.Second)
-	buttonOneChannel := make(chan bool)
 
 	W := dali.NewWindow(700, 700, "", "")
 	t := dali.TitleElement{Text: `Golang, Lorca, HTML5`}
-	scr := dali.ScriptElement{Text: `
-			function initialDisplay(){
-				document.getElementById("pageOne").style.display="block";
-				document.getElementById("pageOne").style.visbility="visible";
-			}`}
 	head := dali.NewHeadElement()
 	head.Elements.AddElement(&t)
-	head.Elements.AddElement(&scr)
 	W.Elements.AddElement(head)
 
-	Tabs := dali.NewDiv("tabs")
-	Tabs.StyleName = "width:600;border:solid 1px #000000;position:relative;"
-	pOneButton := dali.NewButton("Page One", "PageOne", "showPageOne")
-
-	//Bind the menu buttons to a function to display one div and hide the other
-	pOneButton.Binding.BoundFunction = func() {
-		W.GetUI().Eval(`document.getElementById("pageOne").style.display="block";
-		document.getElementById("pageOne").style.visibility="visible";
-		document.getElementById("pageTwo").style.display="none";
-		document.getElementById("pageTwo").style.visibility="hidden";`)
-	}
-	Tabs.Elements.AddElement(pOneButton)
-
-	pTwoButton := dali.NewButton("Page Two", "PageTwo", "showPageTwo")
-	pTwoButton.Binding.BoundFunction = func() {
-		W.GetUI().Eval(`document.getElementById("pageTwo").style.display="block";
-		document.getElementById("pageTwo").style.visibility="visible";
-		document.getElementById("pageOne").style.display="none";
-		document.getElementById("pageOne").style.visibility="hidden";`)
-	}
-	Tabs.Elements.AddElement(pTwoButton)
-
 	clockDiv := dali.NewDiv("clock")
-	clockDiv.StyleName = `display:inline;width:300;position:absolute;right:1px;text-align:right`
+	clockDiv.StyleName = `width:600;text-align:right`
 	clockText := dali.Text(`The Clock Says:`)
 	clockDiv.Elements.AddElement(clockText)
-	Tabs.Elements.AddElement(clockDiv)
 
-	body := dali.NewBodyElement("initialDisplay")
-	body.Elements.AddElement(Tabs)
+	body := dali.NewBodyElement("")
+	body.Elements.AddElement(clockDiv)
 	W.Elements.AddElement(body)
 	PageOne := dali.NewDiv("pageOne")
-	PageOne.StyleName = "display:none;width:600;"
-	PageOne.Elements.AddElement(dali.NewHeader(dali.H1, "heading", "Clicks: 0"))
+	PageOne.StyleName = "width:600;"
+	heading := dali.NewHeader(dali.H1, "heading", "")
+	if err := clicks.BindTextf(heading, "Clicks: %d"); err != nil {
+		log.Fatal(err)
+	}
+	PageOne.Elements.AddElement(heading)
 	coords := dali.NewDiv("coords")
-	coords.Elements.AddElement(dali.Text("You can draw a line if you want"))
+	coords.Elements.AddElement(dali.Text("You can draw on the whiteboard, or have a line drawn if you want"))
 	PageOne.Elements.AddElement(coords)
 	canvas := dali.NewCanvas(600, 400, "whiteboard")
 	canvas.StyleName = "border:1px solid #000000;"
 	PageOne.Elements.AddElement(canvas)
+	whiteboard := canvas.Record()
+
+	// Draw freehand on the whiteboard while the primary button is held down
+	var penX, penY float64
+	pen := canvas.Record()
+	canvas.OnPointer(func(e dali.PointerEvent) {
+		if e.Buttons&1 == 0 {
+			return
+		}
+		switch e.Type {
+		case "pointerdown":
+			penX, penY = e.X, e.Y
+		case "pointermove":
+			pen.BeginPath()
+			pen.MoveTo(penX, penY)
+			pen.LineTo(e.X, e.Y)
+			pen.Stroke()
+			penX, penY = e.X, e.Y
+			if err := pen.FlushOnAnimationFrame(); err != nil {
+				log.Printf("could not draw: %s", err)
+			}
+		}
+	})
 	PageOne.Elements.AddElement(dali.LineBreak())
 	PageOne.Elements.AddElement(dali.LineBreak())
 
-	//Register button1 with server-side function which will emit a boolean on a channel
+	//Register button1 with server-side function which will count the click
 	buttonOne := dali.NewButton("I Count Clicks", "ButtonOne", "do_ButtonOne")
-	buttonOne.Binding.BoundFunction = func() { buttonOneChannel <- true }
+	buttonOne.Binding.BoundFunction = func() {
+		err := clicks.Update(func(n interface{}) interface{} { return n.(int) + 1 })
+		if err != nil {
+			log.Printf("could not count the click: %s", err)
+		}
+	}
 	PageOne.Elements.AddElement(buttonOne)
 
 	buttonTwo := dali.NewButton("Draw A Line", "ButtonTwo", "do_ButtonTwo")
@@ -118,9 +106,12 @@ func DaliExample() {
 	buttonTwo.Binding.BoundFunction = func() {
 		// Re-seed the random number generator to the current time, as of when the button is clicked.
 		rand.Seed(time.Now().UnixNano())
-		x2 = rand.Float32() * 600
-		y2 = rand.Float32() * 400
-		drawALineD(W.GetUI(), x1, y1, x2, y2)
+		x2 = rand.Float64() * 600
+		y2 = rand.Float64() * 400
+		if err := drawALineD(whiteboard, coords, x1, y1, x2, y2); err != nil {
+			log.Printf("could not draw a line: %s", err)
+			return
+		}
 		// Next line will start where this line ends
 		x1 = x2
 		y1 = y2
@@ -129,15 +120,47 @@ func DaliExample() {
 
 	buttonThree := dali.NewButton("Get A Surprise", "ButtonThree", "do_ButtonThree")
 	// Bind button3 to a function that will draw a picture on the whiteboard canvas
-	buttonThree.Binding.BoundFunction = func() { drawAPictureD(W.GetUI()) }
+	buttonThree.Binding.BoundFunction = func() {
+		if err := drawAPictureD(canvas.Context()); err != nil {
+			log.Printf("could not draw the picture: %s", err)
+		}
+	}
 
 	PageOne.Elements.AddElement(buttonThree)
 
-	body.Elements.AddElement(PageOne)
 	PageTwo := dali.NewDiv("pageTwo")
-	PageTwo.StyleName = "display:none"
 	PageTwo.Elements.AddElement(dali.NewHeader(dali.H1, "", "Page Two"))
-	body.Elements.AddElement(PageTwo)
+
+	// The tabs switch between the pages, keeping the whiteboard drawn while it is hidden
+	Tabs := dali.NewTabSet("tabs")
+	Tabs.StyleName = "width:600;border:solid 1px #000000;"
+	if _, err := Tabs.Add("Page One", PageOne, false); err != nil {
+		log.Fatal(err)
+	}
+	if _, err := Tabs.Add("Page Two", PageTwo, false); err != nil {
+		log.Fatal(err)
+	}
+	body.Elements.AddElement(Tabs)
+
+	// The Router gives each tab a path after the # of the page address, so the
+	// pages have a history.  The pages stay in their tabs: the Router selects the
+	// tab of the path it is shown, and is shown the path of the tab selected.
+	router := dali.NewRouter("pages")
+	paths := []string{"/", "/two"}
+	for i, path := range paths {
+		tab := i
+		router.Handle(path, func(dali.Params) dali.Element { return nil }).OnEnter(func(dali.Params) {
+			if err := Tabs.Select(tab); err != nil {
+				log.Printf("could not select the tab: %s", err)
+			}
+		})
+	}
+	Tabs.OnChange(func(tab int, _ *dali.Tab) {
+		if err := router.Navigate(paths[tab]); err != nil {
+			log.Printf("could not navigate: %s", err)
+		}
+	})
+	body.Elements.AddElement(router)
 
 	W.Start()
 	// ui closes when the main method is exited
@@ -146,16 +169,12 @@ func DaliExample() {
 	// Begin an event loop
 	for {
 		select {
-		// Here we are listening to buttonOneChannel, but we could respond to any Go Routine
-		case buttonOne := <-buttonOneChannel:
-			if buttonOne {
-				clicks++
-				changeTitleD(W.GetUI(), fmt.Sprintf("Clicks: %d", clicks))
-			}
-		// for example, we can get the time each second from our ticker
+		// We can respond to any Go Routine, for example we can get the time each second from our ticker
 		case currentTime := <-clock.C:
 			ct := currentTime.Format(time.RFC1123)
-			W.GetUI().Eval(fmt.Sprintf(`document.getElementById("clock").innerHTML="%s";`, ct))
+			if err := clockDiv.SetText(ct); err != nil {
+				log.Printf("could not show the time: %s", err)
+			}
 		// User closed the window.
 		case <-W.GetUI().Done():
 			// This is where we would implement clean shutdown routines
@@ -6,3 +6,5 @@ require (
 	github.com/matthewapeters/dali v0.0.0-20201122185525-06ce0f280045
 	github.com/zserge/lorca v0.1.9
 )
+
+replace github.com/matthewapeters/dali => ./dali